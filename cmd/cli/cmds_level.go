@@ -0,0 +1,161 @@
+package cli
+
+import (
+	il "github.com/faelmori/logz/internal/core"
+
+	"github.com/spf13/cobra"
+
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// LevelCmd creates the command for reading and changing the levels of the running service.
+func LevelCmd() *cobra.Command {
+	var addr, token string
+	cmd := &cobra.Command{
+		Use: "level",
+		Annotations: GetDescriptions(
+			[]string{"Get and set the log levels of the running service"},
+			false,
+		),
+	}
+	cmd.PersistentFlags().StringVarP(&addr, "addr", "a", "", "Address of the service (default http://127.0.0.1:<port>)")
+	cmd.PersistentFlags().StringVarP(&token, "token", "t", os.Getenv("LOGZ_ADMIN_TOKEN"), "Admin API token")
+	client := func() *il.AdminClient {
+		return &il.AdminClient{BaseURL: adminAddress(addr), Token: token, HTTPClient: il.Client()}
+	}
+	cmd.AddCommand(getLevelCmd(client))
+	cmd.AddCommand(setLevelCmd(client))
+	return cmd
+}
+
+// getLevelCmd creates the command printing the levels of the service, or of one module.
+func getLevelCmd(client func() *il.AdminClient) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get [module]",
+		Short: "Show the default and module levels, or the effective level of a module",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if len(args) == 1 {
+				info, err := client().Level(ctx, args[0])
+				if err != nil {
+					fmt.Printf("ErrorCtx getting level: %v\n", err)
+					return
+				}
+				printLoggerInfo(info)
+				return
+			}
+
+			levels, err := client().Levels(ctx)
+			if err != nil {
+				fmt.Printf("ErrorCtx getting levels: %v\n", err)
+				return
+			}
+			fmt.Printf("default: %s", levels.Level)
+			if o, ok := levels.Overrides[""]; ok {
+				fmt.Printf(" (until %s)", o.ExpiresAt.Format(time.RFC3339))
+			}
+			fmt.Println()
+			loggers, err := client().Loggers(ctx)
+			if err != nil {
+				fmt.Printf("ErrorCtx listing loggers: %v\n", err)
+				return
+			}
+			seen := make(map[string]bool)
+			for _, info := range loggers {
+				seen[info.Name] = true
+				printLoggerInfo(info)
+			}
+			for module, level := range levels.Modules {
+				if !seen[module] {
+					printLoggerInfo(il.LoggerInfo{Name: module, Level: level})
+				}
+			}
+		},
+	}
+}
+
+// setLevelCmd creates the command changing the default level or the level of a module.
+func setLevelCmd(client func() *il.AdminClient) *cobra.Command {
+	var ttl time.Duration
+	var reset bool
+	cmd := &cobra.Command{
+		Use:   "set <level|module=level>",
+		Short: "Set the default level, or the level of a module",
+		Example: "  logz level set debug --ttl 10m\n" +
+			"  logz level set db=trace\n" +
+			"  logz level set db --reset",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if reset {
+				if err := client().ResetLevel(ctx, args[0]); err != nil {
+					fmt.Printf("ErrorCtx resetting level: %v\n", err)
+					return
+				}
+				fmt.Printf("Level of %s reset.\n", args[0])
+				return
+			}
+
+			module, name, isModule := strings.Cut(args[0], "=")
+			if !isModule {
+				module, name = "", args[0]
+			}
+			level, err := il.ParseLevel(name)
+			if err != nil {
+				fmt.Printf("ErrorCtx parsing level: %v\n", err)
+				return
+			}
+			if err := client().SetLevel(ctx, module, level, ttl); err != nil {
+				fmt.Printf("ErrorCtx setting level: %v\n", err)
+				return
+			}
+			if module == "" {
+				module = "default"
+			}
+			if ttl > 0 {
+				fmt.Printf("Level of %s set to %s for %s.\n", module, level, ttl)
+			} else {
+				fmt.Printf("Level of %s set to %s.\n", module, level)
+			}
+		},
+	}
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "Revert the level after this duration")
+	cmd.Flags().BoolVar(&reset, "reset", false, "Remove the level of the given module")
+	return cmd
+}
+
+// printLoggerInfo prints the level of a logger.
+func printLoggerInfo(info il.LoggerInfo) {
+	if info.ExpiresAt != nil {
+		fmt.Printf("%s: %s (until %s)\n", info.Name, info.Level, info.ExpiresAt.Format(time.RFC3339))
+		return
+	}
+	fmt.Printf("%s: %s\n", info.Name, info.Level)
+}
+
+// adminAddress returns the address of the admin API: addr when set, otherwise localhost on the
+// port of the running service, or on the configured port.
+func adminAddress(addr string) string {
+	if addr != "" {
+		if !strings.Contains(addr, "://") {
+			addr = "http://" + addr
+		}
+		return addr
+	}
+	port := "9999"
+	if _, p, _, err := il.GetServiceInfo(); err == nil && p != "unknown" && strings.TrimSpace(p) != "" {
+		port = strings.TrimSpace(p)
+	} else if configManager := il.NewConfigManager(); configManager != nil {
+		if cfg := (*configManager).GetConfig(); cfg != nil && cfg.Port() != "" {
+			port = cfg.Port()
+		}
+	}
+	return "http://127.0.0.1:" + port
+}
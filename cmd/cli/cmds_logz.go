@@ -25,6 +25,7 @@ func LogzCmds() []*cobra.Command {
 		newLogCmd("error", []string{"err"}),
 		newLogCmd("fatal", []string{"ftl"}),
 		watchLogsCmd(),
+		readLogsCmd(),
 		startServiceCmd(),
 		stopServiceCmd(),
 		rotateLogsCmd(),
@@ -61,13 +62,16 @@ func newLogCmd(level string, aliases []string) *cobra.Command {
 			}
 
 			if format != "" {
-				config.SetFormat(il.LogFormat(format))
+				config.SetFormat(format)
 			}
 
 			if output != "" {
 				config.SetOutput(output)
 			}
 			logr := il.NewLogger("logz")
+			if format != "" {
+				logr.SetFormat(format)
+			}
 			for k, v := range metaData {
 				logr.SetMetadata(k, v)
 			}
@@ -212,8 +216,9 @@ func archiveLogsCmd() *cobra.Command {
 // watchLogsCmd monitors logs in real-time.
 func watchLogsCmd() *cobra.Command {
 	var mu sync.RWMutex
+	var inputFormat, format string
 
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "watch",
 		Aliases: []string{"w"},
 		Annotations: GetDescriptions(
@@ -250,7 +255,19 @@ func watchLogsCmd() *cobra.Command {
 			}()
 
 			fmt.Println("Monitoring started (Ctrl+C to exit):")
-			if err := reader.Tail(logFilePath, stopChan); err != nil {
+			if inputFormat == "" {
+				err = reader.Tail(logFilePath, stopChan)
+			} else {
+				parser, formatter, pErr := readerFormats(inputFormat, format)
+				if pErr != nil {
+					fmt.Println(pErr)
+					return
+				}
+				err = reader.TailEntries(logFilePath, parser, stopChan, func(entry il.LogzEntry) {
+					printEntry(formatter, entry)
+				})
+			}
+			if err != nil {
 				fmt.Printf("ErrorCtx monitoring logs: %v\n", err)
 			}
 
@@ -258,4 +275,86 @@ func watchLogsCmd() *cobra.Command {
 			time.Sleep(500 * time.Millisecond)
 		},
 	}
+
+	cmd.Flags().StringVarP(&inputFormat, "input-format", "i", "", "Format of the log file to parse (json, logfmt); lines are printed as is when empty")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format of the parsed entries")
+
+	return cmd
+}
+
+// readLogsCmd reads a log file back into entries and prints them in another format.
+func readLogsCmd() *cobra.Command {
+	var inputFormat, format string
+
+	cmd := &cobra.Command{
+		Use:     "read [file]",
+		Aliases: []string{"r"},
+		Args:    cobra.MaximumNArgs(1),
+		Annotations: GetDescriptions(
+			[]string{"Reads a json or logfmt log file and prints its entries"},
+			false,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			parser, formatter, err := readerFormats(inputFormat, format)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			var logFilePath string
+			if len(args) > 0 {
+				logFilePath = args[0]
+			} else {
+				configManager := il.NewConfigManager()
+				if configManager == nil {
+					fmt.Println("ErrorCtx initializing ConfigManager.")
+					return
+				}
+				cfgMgr := *configManager
+
+				config, cfgErr := cfgMgr.LoadConfig()
+				if cfgErr != nil {
+					fmt.Printf("ErrorCtx loading configuration: %v\n", cfgErr)
+					return
+				}
+				logFilePath = config.Output()
+			}
+
+			entries, err := il.NewFileLogReader().ReadEntries(logFilePath, parser)
+			for _, entry := range entries {
+				printEntry(formatter, entry)
+			}
+			if err != nil {
+				fmt.Printf("ErrorCtx reading logs: %v\n", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFormat, "input-format", "i", "logfmt", "Format of the log file (json, logfmt)")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format of the entries")
+
+	return cmd
+}
+
+// readerFormats resolves the parser and formatter used to read a log file back.
+func readerFormats(inputFormat, format string) (il.LogParser, il.LogFormatter, error) {
+	parser := il.NewParser(il.LogFormat(inputFormat))
+	if parser == nil {
+		return nil, nil, fmt.Errorf("unsupported input format: %s", inputFormat)
+	}
+	formatter := il.NewFormatter(il.LogFormat(format))
+	if formatter == nil {
+		return nil, nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+	return parser, formatter, nil
+}
+
+// printEntry prints a parsed entry with the given formatter.
+func printEntry(formatter il.LogFormatter, entry il.LogzEntry) {
+	formatted, err := formatter.Format(entry)
+	if err != nil {
+		fmt.Printf("ErrorCtx formatting entry: %v\n", err)
+		return
+	}
+	fmt.Println(formatted)
 }
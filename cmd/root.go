@@ -90,6 +90,7 @@ func (m *Logz) Command() *cobra.Command {
 	cmd.AddCommand(cc.LogzCmds()...)
 	cmd.AddCommand(cc.ServiceCmd())
 	cmd.AddCommand(cc.MetricsCmd())
+	cmd.AddCommand(cc.LevelCmd())
 
 	cmd.AddCommand(vs.CliCommand())
 
@@ -0,0 +1,281 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AdminConfig holds the settings of the admin API.
+type AdminConfig struct {
+	Prefix string // Path prefix of the endpoints (default "/admin").
+	Token  string // Bearer token required by every request; when empty only loopback clients are served.
+}
+
+// LevelsInfo describes the levels of a logger tree, as returned by GET {prefix}/levels.
+type LevelsInfo struct {
+	Level     LogLevel                 `json:"level"`               // Effective default level.
+	Modules   map[string]LogLevel      `json:"modules,omitempty"`   // Configured module levels.
+	Overrides map[string]LevelOverride `json:"overrides,omitempty"` // Unexpired overrides; "" is the default level.
+}
+
+// LoggerInfo describes a logger, as returned by GET {prefix}/levels/{module} and {prefix}/loggers.
+type LoggerInfo struct {
+	Name      string     `json:"name"`
+	Level     LogLevel   `json:"level"`                // Effective level.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Expiry of the override in effect, if any.
+}
+
+// LevelRequest is the body of the PUT requests of the admin API.
+// A TTL such as "5m" makes the level a temporary override.
+type LevelRequest struct {
+	Level string `json:"level"`
+	TTL   string `json:"ttl,omitempty"`
+}
+
+// RegisterAdminHandlers registers the admin API of logger on mux:
+//
+//	GET    {prefix}/levels           levels of the logger tree
+//	PUT    {prefix}/levels           sets the default level
+//	GET    {prefix}/levels/{module}  effective level of a module
+//	PUT    {prefix}/levels/{module}  sets the level of a module
+//	DELETE {prefix}/levels/{module}  removes the level and the override of a module
+//	GET    {prefix}/loggers          registered loggers and their levels
+//
+// PUT requests take a LevelRequest; with a TTL the level is an override that expires on its own.
+// Without a Token, requests from other hosts than the local one are refused with 403.
+func RegisterAdminHandlers(mux *http.ServeMux, logger LogzLogger, config AdminConfig) {
+	prefix := strings.TrimSuffix(config.Prefix, "/")
+	if prefix == "" {
+		prefix = "/admin"
+	}
+	auth := func(h http.HandlerFunc) http.HandlerFunc {
+		if config.Token == "" {
+			// Without a token the API must not be reachable from other hosts,
+			// since the service binds every interface by default.
+			return func(w http.ResponseWriter, r *http.Request) {
+				if !isLoopbackRequest(r) {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+				h(w, r)
+			}
+		}
+		want := []byte("Bearer " + config.Token)
+		return func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			h(w, r)
+		}
+	}
+
+	mux.HandleFunc("GET "+prefix+"/levels", auth(func(w http.ResponseWriter, _ *http.Request) {
+		writeAdminJSON(w, http.StatusOK, levelsInfo(logger))
+	}))
+	mux.HandleFunc("PUT "+prefix+"/levels", auth(func(w http.ResponseWriter, r *http.Request) {
+		level, ttl, ok := decodeLevelRequest(w, r)
+		if !ok {
+			return
+		}
+		if ttl > 0 {
+			logger.SetLevelOverride("", level, ttl)
+		} else {
+			logger.ClearLevelOverride("")
+			logger.SetLevel(level)
+		}
+		writeAdminJSON(w, http.StatusOK, levelsInfo(logger))
+	}))
+	mux.HandleFunc("GET "+prefix+"/levels/{module}", auth(func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, loggerInfo(logger, r.PathValue("module")))
+	}))
+	mux.HandleFunc("PUT "+prefix+"/levels/{module}", auth(func(w http.ResponseWriter, r *http.Request) {
+		module := r.PathValue("module")
+		level, ttl, ok := decodeLevelRequest(w, r)
+		if !ok {
+			return
+		}
+		if ttl > 0 {
+			logger.SetLevelOverride(module, level, ttl)
+		} else {
+			logger.ClearLevelOverride(module)
+			logger.SetModuleLevel(module, level)
+		}
+		writeAdminJSON(w, http.StatusOK, loggerInfo(logger, module))
+	}))
+	mux.HandleFunc("DELETE "+prefix+"/levels/{module}", auth(func(w http.ResponseWriter, r *http.Request) {
+		module := r.PathValue("module")
+		logger.ClearLevelOverride(module)
+		logger.SetModuleLevel(module, "")
+		writeAdminJSON(w, http.StatusOK, loggerInfo(logger, module))
+	}))
+	mux.HandleFunc("GET "+prefix+"/loggers", auth(func(w http.ResponseWriter, _ *http.Request) {
+		names := logger.Loggers()
+		loggers := make([]LoggerInfo, 0, len(names))
+		for _, name := range names {
+			loggers = append(loggers, loggerInfo(logger, name))
+		}
+		writeAdminJSON(w, http.StatusOK, loggers)
+	}))
+}
+
+// isLoopbackRequest reports whether the request comes from a loopback address.
+func isLoopbackRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// levelsInfo collects the levels of the logger tree.
+func levelsInfo(logger LogzLogger) LevelsInfo {
+	return LevelsInfo{
+		Level:     logger.ModuleLevel(""),
+		Modules:   logger.ModuleLevels(),
+		Overrides: logger.LevelOverrides(),
+	}
+}
+
+// loggerInfo describes the logger named after module.
+func loggerInfo(logger LogzLogger, module string) LoggerInfo {
+	info := LoggerInfo{Name: module, Level: logger.ModuleLevel(module)}
+	if o, ok := logger.LevelOverrides()[module]; ok {
+		info.ExpiresAt = &o.ExpiresAt
+	}
+	return info
+}
+
+// decodeLevelRequest reads the level and TTL of a PUT request, answering 400 when they are invalid.
+func decodeLevelRequest(w http.ResponseWriter, r *http.Request) (LogLevel, time.Duration, bool) {
+	var req LevelRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return "", 0, false
+	}
+	level, err := ParseLevel(req.Level)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", 0, false
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+			http.Error(w, fmt.Sprintf("invalid ttl: %q", req.TTL), http.StatusBadRequest)
+			return "", 0, false
+		}
+	}
+	return level, ttl, true
+}
+
+// writeAdminJSON writes v as the JSON body of the response.
+func writeAdminJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// AdminClient talks to the admin API of a running service.
+type AdminClient struct {
+	BaseURL    string       // Address of the service, e.g. "http://127.0.0.1:9999".
+	Prefix     string       // Path prefix of the admin API (default "/admin").
+	Token      string       // Bearer token sent with every request.
+	HTTPClient *http.Client // Client used for the requests (default http.DefaultClient).
+}
+
+// Levels returns the levels of the logger tree of the service.
+func (c *AdminClient) Levels(ctx context.Context) (LevelsInfo, error) {
+	var info LevelsInfo
+	err := c.do(ctx, http.MethodGet, "/levels", nil, &info)
+	return info, err
+}
+
+// Level returns the effective level of a module of the service.
+func (c *AdminClient) Level(ctx context.Context, module string) (LoggerInfo, error) {
+	var info LoggerInfo
+	err := c.do(ctx, http.MethodGet, "/levels/"+url.PathEscape(module), nil, &info)
+	return info, err
+}
+
+// SetLevel sets the level of a module of the service, or its default level when module is empty.
+// A positive TTL makes the level a temporary override.
+func (c *AdminClient) SetLevel(ctx context.Context, module string, level LogLevel, ttl time.Duration) error {
+	req := LevelRequest{Level: string(level)}
+	if ttl > 0 {
+		req.TTL = ttl.String()
+	}
+	path := "/levels"
+	if module != "" {
+		path += "/" + url.PathEscape(module)
+	}
+	return c.do(ctx, http.MethodPut, path, req, nil)
+}
+
+// ResetLevel removes the level and the override of a module of the service.
+func (c *AdminClient) ResetLevel(ctx context.Context, module string) error {
+	return c.do(ctx, http.MethodDelete, "/levels/"+url.PathEscape(module), nil, nil)
+}
+
+// Loggers returns the loggers registered in the service.
+func (c *AdminClient) Loggers(ctx context.Context) ([]LoggerInfo, error) {
+	var loggers []LoggerInfo
+	err := c.do(ctx, http.MethodGet, "/loggers", nil, &loggers)
+	return loggers, err
+}
+
+// do sends a request to the admin API and decodes the response into out, when not nil.
+func (c *AdminClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	prefix := strings.TrimSuffix(c.Prefix, "/")
+	if prefix == "" {
+		prefix = "/admin"
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+prefix+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("admin API: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Join(errors.New("admin API: invalid response"), err)
+	}
+	return nil
+}
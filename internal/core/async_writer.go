@@ -0,0 +1,283 @@
+package core
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy defines what an AsyncWriter does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the caller until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the entry being written.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued entry to make room.
+	OverflowDropOldest
+	// OverflowDropBelowLevel discards entries below AsyncWriterConfig.DropBelow and blocks for the others.
+	OverflowDropBelowLevel
+)
+
+// AsyncWriterConfig holds the settings of an AsyncWriter.
+type AsyncWriterConfig struct {
+	QueueSize     int            // Maximum number of queued entries (default 1024).
+	Policy        OverflowPolicy // Behavior when the queue is full (default OverflowBlock).
+	DropBelow     LogLevel       // Threshold for OverflowDropBelowLevel (default WARN).
+	BatchSize     int            // Maximum number of entries written at once (default 64).
+	FlushInterval time.Duration  // How long to wait for a batch to fill up; zero writes whatever is queued.
+}
+
+// AsyncWriter is a LogWriter that queues entries and writes them to another writer in the background.
+// Entries are written in batches; when the wrapped writer implements LogBatchWriter the whole batch
+// is handed over at once.
+type AsyncWriter struct {
+	out     LogWriter[any]
+	config  AsyncWriterConfig
+	queue   chan any
+	flushCh chan chan struct{}
+	done    chan struct{}
+
+	closeMu sync.RWMutex
+	closed  bool
+
+	dropped  atomic.Uint64
+	reported uint64 // dropped count already exported to Prometheus, owned by the worker
+}
+
+// NewAsyncWriter creates a new AsyncWriter writing to out and starts its background worker.
+func NewAsyncWriter(out LogWriter[any], config AsyncWriterConfig) *AsyncWriter {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 64
+	}
+	if config.DropBelow == "" {
+		config.DropBelow = WARN
+	}
+	w := &AsyncWriter{
+		out:     out,
+		config:  config,
+		queue:   make(chan any, config.QueueSize),
+		flushCh: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues the entry, applying the overflow policy when the queue is full.
+func (w *AsyncWriter) Write(entry any) error {
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	select {
+	case w.queue <- entry:
+		return nil
+	default:
+	}
+
+	switch w.config.Policy {
+	case OverflowDropNewest:
+		w.dropped.Add(1)
+	case OverflowDropOldest:
+		for {
+			select {
+			case w.queue <- entry:
+				return nil
+			default:
+			}
+			select {
+			case <-w.queue:
+				w.dropped.Add(1)
+			default:
+			}
+		}
+	case OverflowDropBelowLevel:
+		if logLevels[entryLevel(entry)] < logLevels[w.config.DropBelow] {
+			w.dropped.Add(1)
+			return nil
+		}
+		w.queue <- entry
+	default:
+		w.queue <- entry
+	}
+	return nil
+}
+
+// Flush waits until every entry queued before the call has been written and flushes the wrapped writer.
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case w.flushCh <- reply:
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting entries, writes everything still queued and closes the wrapped writer
+// when it implements io.Closer.
+func (w *AsyncWriter) Close() error {
+	w.closeMu.Lock()
+	if w.closed {
+		w.closeMu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.closeMu.Unlock()
+
+	<-w.done
+	if closer, ok := w.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Dropped returns the number of entries discarded by the overflow policy.
+func (w *AsyncWriter) Dropped() uint64 { return w.dropped.Load() }
+
+// run is the background worker draining the queue.
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			batch, reply := w.collect(entry, true)
+			w.writeBatch(batch)
+			if reply != nil {
+				w.flush(reply)
+			}
+		case reply := <-w.flushCh:
+			w.flush(reply)
+		}
+	}
+}
+
+// flush writes every queued entry, flushes the wrapped writer and closes reply.
+func (w *AsyncWriter) flush(reply chan struct{}) {
+	w.drain()
+	if flusher, ok := w.out.(LogFlusher); ok {
+		if err := flusher.Flush(context.Background()); err != nil {
+			log.Printf("ErrorCtx flushing log writer: %v", err)
+		}
+	}
+	close(reply)
+}
+
+// collect starts a batch with the given entry and fills it with queued entries. When wait
+// is set it waits up to FlushInterval for the batch to fill up, unless a flush is requested:
+// the batch is then completed with the entries already queued and the request is returned,
+// to be served once the batch is written.
+func (w *AsyncWriter) collect(first any, wait bool) ([]any, chan struct{}) {
+	batch := make([]any, 1, w.config.BatchSize)
+	batch[0] = first
+	var timeout <-chan time.Time
+	if wait && w.config.FlushInterval > 0 {
+		timer := time.NewTimer(w.config.FlushInterval)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var reply chan struct{}
+	for len(batch) < w.config.BatchSize {
+		if timeout == nil {
+			select {
+			case entry, ok := <-w.queue:
+				if !ok {
+					return batch, reply
+				}
+				batch = append(batch, entry)
+				continue
+			default:
+				return batch, reply
+			}
+		}
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				return batch, nil
+			}
+			batch = append(batch, entry)
+		case reply = <-w.flushCh:
+			timeout = nil
+		case <-timeout:
+			return batch, nil
+		}
+	}
+	return batch, reply
+}
+
+// drain writes every entry currently queued.
+func (w *AsyncWriter) drain() {
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			batch, _ := w.collect(entry, false)
+			w.writeBatch(batch)
+		default:
+			return
+		}
+	}
+}
+
+// writeBatch hands the batch to the wrapped writer and reports the dropped entries.
+func (w *AsyncWriter) writeBatch(batch []any) {
+	if len(batch) > 0 {
+		if bw, ok := w.out.(LogBatchWriter[any]); ok {
+			if err := bw.WriteBatch(batch); err != nil {
+				log.Printf("ErrorCtx writing log: %v", err)
+			}
+		} else {
+			for _, entry := range batch {
+				if err := w.out.Write(entry); err != nil {
+					log.Printf("ErrorCtx writing log: %v", err)
+				}
+			}
+		}
+	}
+	w.reportDropped()
+}
+
+// reportDropped exports the entries dropped since the last report to the PrometheusManager.
+func (w *AsyncWriter) reportDropped() {
+	dropped := w.dropped.Load()
+	if dropped == w.reported {
+		return
+	}
+	pm := GetPrometheusManager()
+	if pm.IsEnabled() {
+		pm.IncrementMetric("logs_dropped_total", float64(dropped-w.reported))
+	}
+	w.reported = dropped
+}
+
+// entryLevel returns the level of an entry handed to a LogWriter[any], or an empty level.
+func entryLevel(entry any) LogLevel {
+	if e, ok := entry.(LogzEntry); ok {
+		return e.GetLevel()
+	}
+	return ""
+}
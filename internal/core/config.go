@@ -50,6 +50,7 @@ type Config interface {
 	Mode() interface{}
 	Level() string
 	SetLevel(VLevel interface{})
+	ModuleLevels() map[string]LogLevel
 	Format() string
 	SetFormat(LogFormat interface{})
 	GetInt(key string, value int) int
@@ -73,15 +74,14 @@ type ConfigImpl struct {
 	VlOutput          string
 	VlNotifierManager NotifierManager
 	VlMode            LogMode
+	VlModuleLevels    map[string]LogLevel
 }
 
 func (c *ConfigImpl) GetFormatter() interface{} {
-	switch c.Format() {
-	case "json":
-		return &JSONFormatter{}
-	default:
-		return &TextFormatter{}
+	if formatter := NewFormatter(LogFormat(c.Format())); formatter != nil {
+		return formatter
 	}
+	return &TextFormatter{}
 }
 func (c *ConfigImpl) Port() string                 { return c.VlPort }
 func (c *ConfigImpl) BindAddress() string          { return c.VlBindAddress }
@@ -94,6 +94,9 @@ func (c *ConfigImpl) NotifierManager() interface{} { return c.VlNotifierManager
 func (c *ConfigImpl) Mode() interface{}            { return c.VlMode }
 func (c *ConfigImpl) Level() string                { return strings.ToUpper(string(c.VlLevel)) }
 func (c *ConfigImpl) SetLevel(VLevel interface{})  { c.VlLevel = LogLevel(VLevel.(string)) }
+func (c *ConfigImpl) ModuleLevels() map[string]LogLevel {
+	return c.VlModuleLevels
+}
 func (c *ConfigImpl) Format() string               { return strings.ToLower(string(c.VlFormat)) }
 func (c *ConfigImpl) SetFormat(format interface{}) { c.VlFormat = LogFormat(format.(string)) }
 func (c *ConfigImpl) Output() string {
@@ -450,12 +453,10 @@ func (cm *ConfigManagerImpl) SetFormat(format interface{}) {
 
 // GetFormatter returns the formatter for the core.
 func (cm *ConfigManagerImpl) GetFormatter() interface{} {
-	switch cm.VConfig.Format() {
-	case "text":
-		return &TextFormatter{}
-	default:
-		return &JSONFormatter{}
+	if formatter := NewFormatter(LogFormat(cm.VConfig.Format())); formatter != nil {
+		return formatter
 	}
+	return &JSONFormatter{}
 }
 
 // LoadConfig loads the configuration from the file and returns a Config instance.
@@ -485,6 +486,15 @@ func (cm *ConfigManagerImpl) LoadConfig() (Config, error) {
 		VMode = defaultMode
 	}
 
+	moduleLevels := make(map[string]LogLevel)
+	for module, name := range viperObj.GetStringMapString("moduleLevels") {
+		level, levelErr := ParseLevel(name)
+		if levelErr != nil {
+			return nil, fmt.Errorf("invalid level of module %s: %w", module, levelErr)
+		}
+		moduleLevels[module] = level
+	}
+
 	VConfig := ConfigImpl{
 		VlPort:            getOrDefault(viperObj.GetString("port"), defaultPort),
 		VlBindAddress:     getOrDefault(viperObj.GetString("bindAddress"), defaultBindAddress),
@@ -496,6 +506,7 @@ func (cm *ConfigManagerImpl) LoadConfig() (Config, error) {
 		VlOutput:          getOrDefault(viperObj.GetString("defaultLogPath"), defaultLogPath),
 		VlNotifierManager: notifierManager,
 		VlMode:            VMode,
+		VlModuleLevels:    moduleLevels,
 	}
 
 	cm.VConfig = &VConfig
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+)
+
+// contextKey is the type used for the values logz stores in a context.Context.
+type contextKey string
+
+const (
+	requestIDKey contextKey = "logz.request_id"
+	traceIDKey   contextKey = "logz.trace_id"
+	spanIDKey    contextKey = "logz.span_id"
+	loggerKey    contextKey = "logz.logger"
+)
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// ContextWithTraceID returns a copy of ctx carrying the given trace ID.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// ContextWithSpanID returns a copy of ctx carrying the given span ID.
+func ContextWithSpanID(ctx context.Context, spanID string) context.Context {
+	return context.WithValue(ctx, spanIDKey, spanID)
+}
+
+// ContextWithLogger returns a copy of ctx carrying a scoped logger.
+// The *Context logging methods route entries through this logger when present.
+func ContextWithLogger(ctx context.Context, logger LogzLogger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, requestIDKey)
+}
+
+// TraceIDFromContext returns the trace ID stored in ctx, if any.
+func TraceIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, traceIDKey)
+}
+
+// SpanIDFromContext returns the span ID stored in ctx, if any.
+func SpanIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, spanIDKey)
+}
+
+// LoggerFromContext returns the scoped logger stored in ctx, or nil.
+func LoggerFromContext(ctx context.Context) LogzLogger {
+	if ctx == nil {
+		return nil
+	}
+	if lgr, ok := ctx.Value(loggerKey).(LogzLogger); ok {
+		return lgr
+	}
+	return nil
+}
+
+// stringFromContext reads a string value from ctx, tolerating a nil context.
+func stringFromContext(ctx context.Context, key contextKey) string {
+	if ctx == nil {
+		return ""
+	}
+	if v, ok := ctx.Value(key).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// contextFields extracts the request and span IDs from ctx as metadata.
+// The trace ID is not included here since it has a dedicated LogEntry field.
+func contextFields(ctx context.Context) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	if spanID := SpanIDFromContext(ctx); spanID != "" {
+		fields["span_id"] = spanID
+	}
+	return fields
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupConfig holds the settings of a DedupWriter.
+type DedupConfig struct {
+	Window time.Duration // How long repeats are collapsed before a summary is written (default 30s).
+	Keys   []string      // Metadata keys that must also match for two entries to be identical.
+}
+
+// DedupWriter is a LogWriter collapsing consecutive identical entries, like syslogd does.
+//
+// The first entry of a series is written at once. The identical entries that follow
+// (same level, source, message and Keys metadata) are counted instead of written, and a
+// single summary entry is written when a different entry arrives, when the window elapses
+// or on Flush and Close. The summary is a copy of the last repeat carrying the
+// "repeat_count", "first_seen" and "last_seen" metadata.
+type DedupWriter struct {
+	out    LogWriter[any]
+	config DedupConfig
+
+	mu        sync.Mutex
+	key       string    // identity of the last written entry
+	last      LogzEntry // last suppressed repeat
+	repeats   int
+	firstSeen time.Time
+	timer     *time.Timer
+	series    uint64 // incremented for every series of repeats, so a stale timer is ignored
+	closed    bool
+}
+
+// NewDedupWriter creates a DedupWriter writing to out.
+func NewDedupWriter(out LogWriter[any], config DedupConfig) *DedupWriter {
+	if config.Window <= 0 {
+		config.Window = 30 * time.Second
+	}
+	return &DedupWriter{out: out, config: config}
+}
+
+// Write writes the entry, or counts it when it repeats the previous one.
+func (w *DedupWriter) Write(entry any) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	e, ok := entry.(LogzEntry)
+	if !ok {
+		err := w.flushSummary()
+		w.key = ""
+		return errors.Join(err, w.out.Write(entry))
+	}
+
+	key := w.identity(e)
+	if key == w.key {
+		if w.repeats == 0 {
+			w.series++
+			series := w.series
+			w.timer = time.AfterFunc(w.config.Window, func() { w.expire(series) })
+		}
+		w.repeats++
+		w.last = e
+		return nil
+	}
+
+	err := w.flushSummary()
+	w.key = key
+	w.firstSeen = e.GetTimestamp()
+	return errors.Join(err, w.out.Write(entry))
+}
+
+// Flush writes the pending summary and flushes the wrapped writer.
+func (w *DedupWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	err := w.flushSummary()
+	w.mu.Unlock()
+	if flusher, ok := w.out.(LogFlusher); ok {
+		err = errors.Join(err, flusher.Flush(ctx))
+	}
+	return err
+}
+
+// Close writes the pending summary and closes the wrapped writer when it implements io.Closer.
+func (w *DedupWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.flushSummary()
+	w.mu.Unlock()
+	if closer, ok := w.out.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// expire writes the summary of a series whose window elapsed.
+// The next identical entry starts a new series and is written at once.
+func (w *DedupWriter) expire(series uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.repeats == 0 || series != w.series {
+		return
+	}
+	if err := w.flushSummary(); err != nil {
+		log.Printf("ErrorCtx writing log: %v", err)
+	}
+	w.key = ""
+}
+
+// flushSummary writes the summary of the pending repeats, if any. The caller must hold w.mu.
+func (w *DedupWriter) flushSummary() error {
+	if w.repeats == 0 {
+		return nil
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	summary := w.last.Clone().
+		AddMetadata("repeat_count", w.repeats).
+		AddMetadata("first_seen", w.firstSeen.Format(time.RFC3339Nano)).
+		AddMetadata("last_seen", w.last.GetTimestamp().Format(time.RFC3339Nano))
+	w.repeats = 0
+	w.last = nil
+	return w.out.Write(summary)
+}
+
+// identity returns the key under which identical entries are collapsed.
+func (w *DedupWriter) identity(e LogzEntry) string {
+	var b strings.Builder
+	b.WriteString(string(e.GetLevel()))
+	b.WriteByte(0)
+	b.WriteString(e.GetSource())
+	b.WriteByte(0)
+	b.WriteString(e.GetMessage())
+	metadata := e.GetMetadata()
+	for _, k := range w.config.Keys {
+		b.WriteByte(0)
+		if v, ok := metadata[k]; ok {
+			fmt.Fprintf(&b, "%s=%v", k, v)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,292 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultElasticIndex is the index pattern of an ElasticWriter, e.g. "logz-2026.10.16".
+const defaultElasticIndex = "logz-{2006.01.02}"
+
+// ElasticConfig holds the settings of an ElasticWriter.
+type ElasticConfig struct {
+	URL         string         // Base URL of the cluster, e.g. "https://es:9200"; "/_bulk" is appended when missing.
+	Index       string         // Index name; a Go time layout in braces is replaced by the UTC timestamp of the entry (default "logz-{2006.01.02}").
+	OpType      string         // Bulk action, "create" (default, required by data streams) or "index".
+	Mapping     *JSONFieldMap  // Mapping of the documents; defaults to ECSFieldMap().
+	BearerToken string         // Sent as "Authorization: Bearer <token>" when set.
+	APIKey      string         // Sent as "Authorization: ApiKey <key>" when set and BearerToken is empty.
+	Username    string         // Basic auth user, used when neither BearerToken nor APIKey is set.
+	Password    string         // Basic auth password.
+	Gzip        bool           // Compress the request body with gzip.
+	BatchSize   int            // Maximum number of documents per bulk request (default 500).
+	BatchWait   time.Duration  // Maximum age of a batch before it is sent (default 1s).
+	QueueSize   int            // Maximum number of queued entries (default 10000).
+	Overflow    OverflowPolicy // Behavior when the queue is full (default OverflowBlock).
+	Retry       RetryConfig    // Retries of the failed requests and of the rejected documents.
+	Timeout     time.Duration  // Timeout of each bulk request (default 30s).
+	HTTPClient  *http.Client   // Client of the requests; defaults to a client with Timeout.
+}
+
+// ElasticWriter is a LogWriter that batches entries and indexes them in Elasticsearch or
+// OpenSearch through the _bulk API.
+//
+// Entries are queued and sent in the background by an AsyncWriter. Each entry is indexed in
+// the index named after its timestamp and mapped to a document by Mapping. When a bulk request
+// partially fails, only the documents rejected with 429 or 5xx are sent again, with backoff;
+// documents rejected for other reasons, such as mapping conflicts, are dropped and reported
+// in the returned error, which the AsyncWriter logs.
+type ElasticWriter struct {
+	async  *AsyncWriter
+	cancel context.CancelFunc // Cancels the requests of the client.
+}
+
+// elasticClient sends bulk requests; it is the output of the AsyncWriter of an ElasticWriter.
+type elasticClient struct {
+	config ElasticConfig
+	url    string
+	http   *http.Client
+	ctx    context.Context // Context of the requests, cancelled when Close gives up.
+}
+
+// elasticDoc is a document of a bulk request: its action line and its source.
+type elasticDoc struct {
+	action []byte
+	source string
+}
+
+// elasticBulkResponse is the part of the _bulk response used to find the rejected documents.
+type elasticBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// NewElasticWriter creates an ElasticWriter and starts its background worker.
+func NewElasticWriter(config ElasticConfig) (*ElasticWriter, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("elastic writer requires a URL")
+	}
+	if config.Index == "" {
+		config.Index = defaultElasticIndex
+	}
+	switch config.OpType {
+	case "":
+		config.OpType = "create"
+	case "create", "index":
+	default:
+		return nil, fmt.Errorf("unsupported bulk operation: %s", config.OpType)
+	}
+	if config.Mapping == nil {
+		m := ECSFieldMap()
+		config.Mapping = &m
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if config.BatchWait <= 0 {
+		config.BatchWait = time.Second
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10000
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	config.Retry = config.Retry.withDefaults()
+
+	url := strings.TrimSuffix(config.URL, "/")
+	if !strings.HasSuffix(url, "/_bulk") {
+		url += "/_bulk"
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &elasticClient{config: config, url: url, http: client, ctx: ctx}
+
+	return &ElasticWriter{
+		async: NewAsyncWriter(c, AsyncWriterConfig{
+			QueueSize:     config.QueueSize,
+			Policy:        config.Overflow,
+			BatchSize:     config.BatchSize,
+			FlushInterval: config.BatchWait,
+		}),
+		cancel: cancel,
+	}, nil
+}
+
+// Write queues the entry for the next bulk request.
+func (w *ElasticWriter) Write(entry any) error {
+	return w.async.Write(entry)
+}
+
+// Flush sends every queued entry, giving up when ctx is done.
+func (w *ElasticWriter) Flush(ctx context.Context) error {
+	return w.async.Flush(ctx)
+}
+
+// Close sends the queued entries, retrying as configured by Retry, and stops the background worker.
+// Requests still pending after 5s are cancelled and their entries dropped.
+func (w *ElasticWriter) Close() error {
+	return closePushWriter(w.async, w.cancel)
+}
+
+// Dropped returns the number of entries discarded because the queue was full.
+func (w *ElasticWriter) Dropped() uint64 {
+	return w.async.Dropped()
+}
+
+// Write indexes a single entry.
+func (c *elasticClient) Write(entry any) error {
+	return c.WriteBatch([]any{entry})
+}
+
+// WriteBatch indexes the entries with bulk requests, sending the rejected documents again
+// while they fail with a retryable status. Entries that cannot be encoded are reported in the
+// returned error; the others are indexed.
+func (c *elasticClient) WriteBatch(entries []any) error {
+	var errs []error
+	docs := make([]elasticDoc, 0, len(entries))
+	for _, entry := range entries {
+		doc, err := c.document(entry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	for retry := 0; len(docs) > 0; retry++ {
+		if retry > 0 {
+			timer := time.NewTimer(c.config.Retry.backoff(retry))
+			select {
+			case <-timer.C:
+			case <-c.ctx.Done():
+				timer.Stop()
+				errs = append(errs, fmt.Errorf("%d documents not indexed: %w", len(docs), c.ctx.Err()))
+				return errors.Join(errs...)
+			}
+		}
+		rejected, failed, err := c.bulk(docs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("indexing %d documents: %w", len(docs), err))
+			return errors.Join(errs...)
+		}
+		errs = append(errs, failed...)
+		docs = rejected
+		if retry >= c.config.Retry.MaxRetries && len(docs) > 0 {
+			errs = append(errs, fmt.Errorf("%d documents still rejected after %d retries", len(docs), retry))
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// document builds the bulk action and the source of an entry.
+func (c *elasticClient) document(entry any) (elasticDoc, error) {
+	var e LogzEntry
+	switch v := entry.(type) {
+	case LogzEntry:
+		e = v
+	case []byte:
+		e = NewLogEntry().WithLevel(INFO).WithMessage(string(v))
+	default:
+		return elasticDoc{}, fmt.Errorf("unsupported log entry type: %T", entry)
+	}
+	source, err := c.config.Mapping.format(e)
+	if err != nil {
+		return elasticDoc{}, err
+	}
+	action, err := json.Marshal(map[string]interface{}{
+		c.config.OpType: map[string]string{"_index": elasticIndex(c.config.Index, e.GetTimestamp())},
+	})
+	if err != nil {
+		return elasticDoc{}, err
+	}
+	return elasticDoc{action: action, source: source}, nil
+}
+
+// bulk sends one bulk request. It returns the documents rejected with a retryable status,
+// and the errors of the documents rejected for good.
+func (c *elasticClient) bulk(docs []elasticDoc) (rejected []elasticDoc, failed []error, err error) {
+	var b bytes.Buffer
+	for _, doc := range docs {
+		b.Write(doc.action)
+		b.WriteByte('\n')
+		b.WriteString(doc.source)
+		b.WriteByte('\n')
+	}
+	data := b.Bytes()
+	if c.config.Gzip {
+		if data, err = gzipBytes(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	body, err := pushWithRetry(c.ctx, c.http, c.config.Retry, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		if c.config.Gzip {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		if c.config.BearerToken == "" && c.config.APIKey != "" {
+			req.Header.Set("Authorization", "ApiKey "+c.config.APIKey)
+		} else {
+			setAuthHeader(req, c.config.BearerToken, c.config.Username, c.config.Password)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp elasticBulkResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, nil, fmt.Errorf("decoding bulk response: %w", err)
+	}
+	if !resp.Errors {
+		return nil, nil, nil
+	}
+	if len(resp.Items) != len(docs) {
+		return nil, nil, fmt.Errorf("bulk response has %d items for %d documents", len(resp.Items), len(docs))
+	}
+	for i, item := range resp.Items {
+		for _, result := range item {
+			switch {
+			case result.Status < 300:
+			case retryableStatus(result.Status):
+				rejected = append(rejected, docs[i])
+			default:
+				failed = append(failed, fmt.Errorf("document rejected with status %d: %s", result.Status, result.Error))
+			}
+		}
+	}
+	return rejected, failed, nil
+}
+
+// elasticIndex returns the index of an entry logged at ts: the pattern with the time layout
+// in braces, if any, replaced by ts in UTC.
+func elasticIndex(pattern string, ts time.Time) string {
+	start := strings.IndexByte(pattern, '{')
+	end := strings.LastIndexByte(pattern, '}')
+	if start < 0 || end < start {
+		return pattern
+	}
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	return pattern[:start] + ts.UTC().Format(pattern[start+1:end]) + pattern[end+1:]
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxErrorDepth bounds the depth of the error chain recorded by NewErrorInfo.
+const maxErrorDepth = 32
+
+// ErrorInfo is the structured form of an error attached to a log entry.
+//
+// Causes holds the wrapped errors: a single one for errors implementing Unwrap() error,
+// as returned by fmt.Errorf with %w, and one per error for Unwrap() []error, as returned
+// by errors.Join. Stack is only set on the outermost error, when captured.
+type ErrorInfo struct {
+	Message string      `json:"message"`          // Text of the error.
+	Type    string      `json:"type"`             // Concrete type of the error, e.g. "*fs.PathError".
+	Causes  []ErrorInfo `json:"causes,omitempty"` // Wrapped errors.
+	Stack   []string    `json:"stack,omitempty"`  // Call stack of the log call, innermost frame first.
+}
+
+// NewErrorInfo records the message, the concrete type and the wrapped errors of err.
+// It returns nil when err is nil.
+func NewErrorInfo(err error) *ErrorInfo {
+	if err == nil {
+		return nil
+	}
+	info := newErrorInfo(err, 0)
+	return &info
+}
+
+// newErrorInfo records err and its causes, down to maxErrorDepth.
+func newErrorInfo(err error, depth int) ErrorInfo {
+	info := ErrorInfo{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	if depth >= maxErrorDepth {
+		return info
+	}
+	var causes []error
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		causes = u.Unwrap()
+	case interface{ Unwrap() error }:
+		causes = []error{u.Unwrap()}
+	}
+	for _, cause := range causes {
+		if cause != nil {
+			info.Causes = append(info.Causes, newErrorInfo(cause, depth+1))
+		}
+	}
+	return info
+}
+
+// String renders the error and its causes as indented lines, followed by the stack when set.
+func (e *ErrorInfo) String() string {
+	var b strings.Builder
+	e.write(&b, "")
+	if len(e.Stack) > 0 {
+		b.WriteString("stack:")
+		for _, frame := range e.Stack {
+			b.WriteString("\n  ")
+			b.WriteString(frame)
+		}
+		b.WriteByte('\n')
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// write renders the error and its causes with the given indentation.
+func (e *ErrorInfo) write(b *strings.Builder, indent string) {
+	b.WriteString(indent)
+	if indent != "" {
+		b.WriteString("caused by: ")
+	}
+	fmt.Fprintf(b, "%s (%s)\n", e.Message, e.Type)
+	for i := range e.Causes {
+		e.Causes[i].write(b, indent+"  ")
+	}
+}
+
+// SetStackTraceLevel sets the lowest level at which entries carrying an error (see WithError)
+// capture the call stack of the log call. An empty level disables stack capture, the default.
+func (l *LogzCoreImpl) SetStackTraceLevel(level LogLevel) {
+	if l.parent != nil {
+		l.root().SetStackTraceLevel(level)
+		return
+	}
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+	l.stackLevel = level
+}
+
+// WithError returns a child logger attaching err, with its chain, to every entry it logs.
+func (l *LogzCoreImpl) WithError(err error) LogzLogger {
+	c := l.child(l.Name(), l.module, l.fields)
+	c.err = err
+	return c
+}
+
+// errorInfo returns the error attached to the entries of l at the given level, if any,
+// with the call stack when the level reaches the stack trace level.
+func (l *LogzCoreImpl) errorInfo(level LogLevel) *ErrorInfo {
+	info := NewErrorInfo(l.err)
+	if info == nil {
+		return nil
+	}
+	r := l.root()
+	r.Mu.RLock()
+	stackLevel := r.stackLevel
+	r.Mu.RUnlock()
+	if stackLevel != "" && logLevels[level] >= logLevels[stackLevel] {
+		info.Stack = callStack()
+	}
+	return info
+}
+
+// loggerFramePrefixes are the functions skipped at the top of a captured stack,
+// so that it starts at the code calling the logger.
+var loggerFramePrefixes = []string{
+	"github.com/faelmori/logz/internal/core.(*LogzCoreImpl).",
+	"github.com/faelmori/logz/internal/core.logContextAt",
+	"github.com/faelmori/logz/logger.",
+	"github.com/faelmori/logz.",
+}
+
+// callStack returns the frames of the current goroutine below the logger, as "file:line function".
+func callStack() []string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var stack []string
+	inLogger := true
+	for {
+		frame, more := frames.Next()
+		if inLogger && !isLoggerFrame(frame.Function) {
+			inLogger = false
+		}
+		if !inLogger {
+			stack = append(stack, fmt.Sprintf("%s:%d %s", trimFilePath(frame.File), frame.Line, frame.Function))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// callerInfo returns the first frame below the logger, as "file:line function".
+func callerInfo() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isLoggerFrame(frame.Function) {
+			return fmt.Sprintf("%s:%d %s", trimFilePath(frame.File), frame.Line, frame.Function)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// isLoggerFrame reports whether function belongs to the logging call path.
+func isLoggerFrame(function string) bool {
+	for _, prefix := range loggerFramePrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
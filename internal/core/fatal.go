@@ -0,0 +1,146 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// FatalMode selects what a FATAL entry does once it has been written.
+type FatalMode int
+
+const (
+	// FatalExit flushes and closes the writers and notifiers, then calls the exit function with status 1.
+	FatalExit FatalMode = iota
+	// FatalPanic flushes the writers and notifiers, then panics with a *PanicError,
+	// so deferred functions run and tests can recover.
+	FatalPanic
+)
+
+// fatalFlushTimeout bounds the time spent flushing the writers before the process terminates.
+const fatalFlushTimeout = 5 * time.Second
+
+// PanicError is the value passed to panic by PANIC entries and by FATAL entries in FatalPanic mode.
+type PanicError struct {
+	Entry LogzEntry // Entry that caused the panic.
+}
+
+// Error returns the message of the entry.
+func (e *PanicError) Error() string { return e.Entry.GetMessage() }
+
+// SetExitFunc sets the function called by FATAL entries to terminate the process; nil restores os.Exit.
+// Tests replace it to exercise FATAL paths without exiting.
+func (l *LogzCoreImpl) SetExitFunc(exit func(code int)) {
+	if l.parent != nil {
+		l.root().SetExitFunc(exit)
+		return
+	}
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+	l.exitFunc = exit
+}
+
+// SetFatalMode sets whether FATAL entries exit the process or panic.
+func (l *LogzCoreImpl) SetFatalMode(mode FatalMode) {
+	if l.parent != nil {
+		l.root().SetFatalMode(mode)
+		return
+	}
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+	l.fatalMode = mode
+}
+
+// Flush waits for the pending notifications, reports the entries sampled out so far, then
+// flushes the writer and the notifiers implementing LogFlusher, giving up when ctx is done.
+func (l *LogzCoreImpl) Flush(ctx context.Context) error {
+	r := l.root()
+	notified := make(chan struct{})
+	go func() {
+		r.notifyWg.Wait()
+		close(notified)
+	}()
+	var errs []error
+	select {
+	case <-notified:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("waiting for the notifications: %w", ctx.Err()))
+	}
+	if sampler := r.getSampler(); sampler != nil {
+		errs = append(errs, sampler.Flush(ctx))
+	}
+	for _, sink := range r.sinks() {
+		if flusher, ok := sink.(LogFlusher); ok {
+			errs = append(errs, flusher.Flush(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes, then closes the writer and the notifiers implementing io.Closer.
+// Entries logged afterwards fail with the errors of the closed writers.
+func (l *LogzCoreImpl) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+	defer cancel()
+	errs := []error{l.Flush(ctx)}
+	for _, sink := range l.root().sinks() {
+		if closer, ok := sink.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sinks returns the writer and the notifiers of the logger. It must be called on a root logger.
+func (l *LogzCoreImpl) sinks() []interface{} {
+	l.Mu.RLock()
+	writer, config := l.VWriter, l.VConfig
+	l.Mu.RUnlock()
+
+	var sinks []interface{}
+	if writer != nil {
+		sinks = append(sinks, writer)
+	}
+	if config != nil {
+		if nm, ok := config.NotifierManager().(NotifierManager); ok && nm != nil {
+			for _, name := range nm.ListNotifiers() {
+				if notifier, ok := nm.GetNotifier(name); ok && notifier != nil {
+					sinks = append(sinks, notifier)
+				}
+			}
+		}
+	}
+	return sinks
+}
+
+// terminate ends the flow of a PANIC or FATAL entry once it has been dispatched.
+// PANIC entries flush the sinks and panic. FATAL entries panic the same way in FatalPanic
+// mode; otherwise they flush and close the sinks, then call the exit function.
+func (l *LogzCoreImpl) terminate(entry LogzEntry) {
+	r := l.root()
+	r.Mu.RLock()
+	exit, mode := r.exitFunc, r.fatalMode
+	r.Mu.RUnlock()
+
+	if entry.GetLevel() == PANIC || mode == FatalPanic {
+		ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+		err := r.Flush(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("ErrorCtx flushing logs: %v", err)
+		}
+		panic(&PanicError{Entry: entry})
+	}
+
+	if err := r.Close(); err != nil {
+		log.Printf("ErrorCtx closing logs: %v", err)
+	}
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(1)
+}
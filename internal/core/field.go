@@ -0,0 +1,218 @@
+package core
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// FieldType tells how the value of a Field is stored.
+type FieldType uint8
+
+const (
+	StringType   FieldType = iota + 1 // String holds the value.
+	IntType                           // Integer holds an int.
+	Int64Type                         // Integer holds an int64.
+	Float64Type                       // Integer holds the bits of a float64.
+	BoolType                          // Integer is 1 for true.
+	DurationType                      // Integer holds the nanoseconds.
+	ErrorType                         // Interface holds the error.
+	AnyType                           // Interface holds the value.
+	ObjectType                        // Interface holds an ObjectMarshaler.
+)
+
+// Field is a typed key-value pair attached to an entry by the level methods such as Info.
+//
+// Fields of scalar types carry their value without boxing it in an interface, so
+// building them costs nothing when the level is disabled. They are converted into
+// metadata only for entries that are logged; when several fields share a key, the
+// last one wins. Formatters write the metadata sorted by key.
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	String    string
+	Interface interface{}
+}
+
+// ObjectMarshaler is implemented by values logged with Object.
+// MarshalLogObject is only called for entries that are logged.
+type ObjectMarshaler interface {
+	MarshalLogObject() []Field
+}
+
+// String returns a field holding a string.
+func String(key, value string) Field {
+	return Field{Key: key, Type: StringType, String: value}
+}
+
+// Int returns a field holding an int.
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: IntType, Integer: int64(value)}
+}
+
+// Int64 returns a field holding an int64.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: Int64Type, Integer: value}
+}
+
+// Float64 returns a field holding a float64.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: Float64Type, Integer: int64(math.Float64bits(value))}
+}
+
+// Bool returns a field holding a bool.
+func Bool(key string, value bool) Field {
+	var i int64
+	if value {
+		i = 1
+	}
+	return Field{Key: key, Type: BoolType, Integer: i}
+}
+
+// Duration returns a field holding a time.Duration.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, Integer: int64(value)}
+}
+
+// Err returns a field recording err, with its chain, as the error of the entry (see WithError).
+// A nil error adds nothing.
+func Err(err error) Field {
+	return Field{Key: "error", Type: ErrorType, Interface: err}
+}
+
+// Any returns a field holding an arbitrary value, rendered by the formatters as metadata.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Type: AnyType, Interface: value}
+}
+
+// Object returns a field holding the fields of value as a nested map.
+func Object(key string, value ObjectMarshaler) Field {
+	return Field{Key: key, Type: ObjectType, Interface: value}
+}
+
+// Value returns the value of the field as stored in the metadata of an entry.
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.String
+	case IntType:
+		return int(f.Integer)
+	case Int64Type:
+		return f.Integer
+	case Float64Type:
+		return math.Float64frombits(uint64(f.Integer))
+	case BoolType:
+		return f.Integer == 1
+	case DurationType:
+		return time.Duration(f.Integer)
+	case ObjectType:
+		m, ok := f.Interface.(ObjectMarshaler)
+		if !ok || m == nil {
+			return nil
+		}
+		return fieldsMap(m.MarshalLogObject())
+	default:
+		return f.Interface
+	}
+}
+
+// addTo records the field in the entry.
+func (f Field) addTo(entry LogzEntry) {
+	if f.Type == ErrorType {
+		if err, ok := f.Interface.(error); ok && err != nil {
+			entry.WithError(err)
+		}
+		return
+	}
+	entry.AddMetadata(f.Key, f.Value())
+}
+
+// fieldsMap converts fields into a map, the last field winning for duplicate keys.
+func fieldsMap(fields []Field) map[string]interface{} {
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if f.Type == ErrorType {
+			if err, ok := f.Interface.(error); ok && err != nil {
+				m[f.Key] = NewErrorInfo(err)
+			}
+			continue
+		}
+		m[f.Key] = f.Value()
+	}
+	return m
+}
+
+// logFields logs a message with typed fields. Callers check Enabled first, so that
+// nothing is allocated when the level is disabled, except for PANIC and FATAL: those
+// are not written when disabled, but they still panic or exit.
+func (l *LogzCoreImpl) logFields(level LogLevel, msg string, fields []Field) {
+	entry := l.newEntry(context.Background(), level, msg, nil)
+	for _, f := range fields {
+		f.addTo(entry)
+	}
+	if !l.Enabled(level) {
+		l.terminate(entry)
+		return
+	}
+	l.process(entry)
+}
+
+// Debug logs a debug message with typed fields.
+func (l *LogzCoreImpl) Debug(msg string, fields ...Field) {
+	if l.Enabled(DEBUG) {
+		l.logFields(DEBUG, msg, fields)
+	}
+}
+
+// Trace logs a trace message with typed fields.
+func (l *LogzCoreImpl) Trace(msg string, fields ...Field) {
+	if l.Enabled(TRACE) {
+		l.logFields(TRACE, msg, fields)
+	}
+}
+
+// Info logs an info message with typed fields.
+func (l *LogzCoreImpl) Info(msg string, fields ...Field) {
+	if l.Enabled(INFO) {
+		l.logFields(INFO, msg, fields)
+	}
+}
+
+// Notice logs a notice message with typed fields.
+func (l *LogzCoreImpl) Notice(msg string, fields ...Field) {
+	if l.Enabled(NOTICE) {
+		l.logFields(NOTICE, msg, fields)
+	}
+}
+
+// Success logs a success message with typed fields.
+func (l *LogzCoreImpl) Success(msg string, fields ...Field) {
+	if l.Enabled(SUCCESS) {
+		l.logFields(SUCCESS, msg, fields)
+	}
+}
+
+// Warn logs a warning message with typed fields.
+func (l *LogzCoreImpl) Warn(msg string, fields ...Field) {
+	if l.Enabled(WARN) {
+		l.logFields(WARN, msg, fields)
+	}
+}
+
+// Error logs an error message with typed fields.
+func (l *LogzCoreImpl) Error(msg string, fields ...Field) {
+	if l.Enabled(ERROR) {
+		l.logFields(ERROR, msg, fields)
+	}
+}
+
+// PanicFields logs a panic message with typed fields, flushes the writers and panics with a *PanicError.
+func (l *LogzCoreImpl) PanicFields(msg string, fields ...Field) {
+	l.logFields(PANIC, msg, fields)
+}
+
+// FatalFields logs a fatal message with typed fields and terminates the process (see SetFatalMode).
+func (l *LogzCoreImpl) FatalFields(msg string, fields ...Field) {
+	l.logFields(FATAL, msg, fields)
+}
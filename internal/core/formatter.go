@@ -0,0 +1,316 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewFormatter returns the formatter of the given format, or nil if the format is unknown.
+// A JSON field mapping preset can be selected with "json:<preset>", e.g. "json:ecs".
+func NewFormatter(format LogFormat) LogFormatter {
+	if name, preset, ok := strings.Cut(string(format), ":"); ok && LogFormat(strings.ToLower(name)) == JSON {
+		fieldMap, found := JSONFieldMapPreset(preset)
+		if !found {
+			return nil
+		}
+		return NewJSONFormatter(fieldMap)
+	}
+	switch LogFormat(strings.ToLower(string(format))) {
+	case JSON:
+		return &JSONFormatter{}
+	case TEXT:
+		return &TextFormatter{}
+	case YAML:
+		return &YAMLFormatter{}
+	case XML:
+		return &XMLFormatter{}
+	case RAW:
+		return &RawFormatter{}
+	case LOGFMT:
+		return &LogfmtFormatter{}
+	default:
+		return nil
+	}
+}
+
+// entryFields returns the fields of an entry that are not exposed by the LogzEntry interface.
+func entryFields(entry LogzEntry) (le LogEntry) {
+	if e, ok := entry.(*LogEntry); ok {
+		return *e
+	}
+	return LogEntry{
+		Timestamp: entry.GetTimestamp(),
+		Level:     entry.GetLevel(),
+		Source:    entry.GetSource(),
+		Context:   entry.GetContext(),
+		Message:   entry.GetMessage(),
+		Metadata:  entry.GetMetadata(),
+		Severity:  logLevels[entry.GetLevel()],
+		Error:     entry.GetError(),
+	}
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeValue converts structs and other composite values into maps, slices and scalars
+// through a JSON round trip, so every formatter renders them the same way.
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case time.Duration:
+		return val.String()
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	case map[string]interface{}:
+		return val
+	case []interface{}:
+		return val
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	var out interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return out
+}
+
+// YAMLFormatter formats each log entry as a YAML document.
+//
+// Every document starts with "---" and lists its keys in a fixed order:
+// timestamp, level, severity, source, message, then context, trace_id, caller,
+// hostname and pid when they are set, followed by the "tags" and "metadata" mappings.
+// Strings, as well as the keys of tags and metadata, are always double-quoted.
+// Timestamps use RFC 3339 with nanoseconds and the keys of tags and metadata are sorted.
+type YAMLFormatter struct{}
+
+// Format converts the log entry to a YAML document.
+func (f *YAMLFormatter) Format(entry LogzEntry) (string, error) {
+	le := entryFields(entry)
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	writeYAMLScalar(&b, 0, "timestamp", le.Timestamp.Format(time.RFC3339Nano))
+	writeYAMLScalar(&b, 0, "level", string(le.Level))
+	writeYAMLScalar(&b, 0, "severity", le.Severity)
+	writeYAMLScalar(&b, 0, "source", le.Source)
+	writeYAMLScalar(&b, 0, "message", le.Message)
+	for _, kv := range []struct{ key, value string }{
+		{"context", le.Context},
+		{"trace_id", le.TraceID},
+		{"caller", le.Caller},
+		{"hostname", le.Hostname},
+	} {
+		if kv.value != "" {
+			writeYAMLScalar(&b, 0, kv.key, kv.value)
+		}
+	}
+	if le.ProcessID != 0 {
+		writeYAMLScalar(&b, 0, "pid", le.ProcessID)
+	}
+	if len(le.Tags) > 0 {
+		b.WriteString("tags:\n")
+		for _, k := range sortedKeys(le.Tags) {
+			writeYAMLScalar(&b, 1, yamlString(k), le.Tags[k])
+		}
+	}
+	if len(le.Metadata) > 0 {
+		b.WriteString("metadata:\n")
+		writeYAMLMap(&b, 1, le.Metadata)
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// writeYAMLMap writes the sorted entries of m at the given indentation depth.
+func writeYAMLMap(b *strings.Builder, depth int, m map[string]interface{}) {
+	for _, k := range sortedKeys(m) {
+		writeYAMLValue(b, depth, yamlString(k)+":", normalizeValue(m[k]))
+	}
+}
+
+// writeYAMLValue writes a key (or sequence dash) followed by its value at the given depth.
+func writeYAMLValue(b *strings.Builder, depth int, prefix string, v interface{}) {
+	indent := strings.Repeat("  ", depth)
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			fmt.Fprintf(b, "%s%s {}\n", indent, prefix)
+			return
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, prefix)
+		writeYAMLMap(b, depth+1, val)
+	case []interface{}:
+		if len(val) == 0 {
+			fmt.Fprintf(b, "%s%s []\n", indent, prefix)
+			return
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, prefix)
+		for _, item := range val {
+			writeYAMLValue(b, depth+1, "-", normalizeValue(item))
+		}
+	default:
+		fmt.Fprintf(b, "%s%s %s\n", indent, prefix, yamlScalar(val))
+	}
+}
+
+// writeYAMLScalar writes a key with a scalar value at the given depth.
+// The key is written as given; callers quote keys that are not part of the fixed layout.
+func writeYAMLScalar(b *strings.Builder, depth int, key string, v interface{}) {
+	fmt.Fprintf(b, "%s%s: %s\n", strings.Repeat("  ", depth), key, yamlScalar(v))
+}
+
+// yamlScalar renders a scalar value; strings are double-quoted.
+func yamlScalar(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return yamlString(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case json.Number:
+		return val.String()
+	case float32, float64:
+		return fmt.Sprintf("%v", val)
+	}
+	if rv := reflect.ValueOf(v); rv.CanInt() || rv.CanUint() {
+		return fmt.Sprintf("%d", v)
+	}
+	return yamlString(fmt.Sprintf("%v", v))
+}
+
+// yamlString renders s as a double-quoted YAML string.
+// JSON string escapes are a subset of the YAML double-quoted style.
+func yamlString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// XMLFormatter formats each log entry as a single-line XML element.
+//
+// The layout is:
+//
+//	<entry>
+//	  <timestamp/><level/><severity/><source/><message/>
+//	  <context/><trace_id/><caller/><hostname/><pid/>   (only when set)
+//	  <tags><tag key="k">v</tag>...</tags>               (only when set)
+//	  <metadata><field key="k">v</field>...</metadata>   (only when set)
+//	</entry>
+//
+// Nested maps render as fields containing fields, slices as fields containing
+// <item> elements. Tags and fields are sorted by key.
+type XMLFormatter struct{}
+
+// Format converts the log entry to an XML element.
+func (f *XMLFormatter) Format(entry LogzEntry) (string, error) {
+	le := entryFields(entry)
+	var b bytes.Buffer
+
+	b.WriteString("<entry>")
+	writeXMLElement(&b, "timestamp", le.Timestamp.Format(time.RFC3339Nano))
+	writeXMLElement(&b, "level", string(le.Level))
+	writeXMLElement(&b, "severity", strconv.Itoa(le.Severity))
+	writeXMLElement(&b, "source", le.Source)
+	writeXMLElement(&b, "message", le.Message)
+	for _, kv := range []struct{ key, value string }{
+		{"context", le.Context},
+		{"trace_id", le.TraceID},
+		{"caller", le.Caller},
+		{"hostname", le.Hostname},
+	} {
+		if kv.value != "" {
+			writeXMLElement(&b, kv.key, kv.value)
+		}
+	}
+	if le.ProcessID != 0 {
+		writeXMLElement(&b, "pid", strconv.Itoa(le.ProcessID))
+	}
+	if len(le.Tags) > 0 {
+		b.WriteString("<tags>")
+		for _, k := range sortedKeys(le.Tags) {
+			b.WriteString(`<tag key="`)
+			_ = xml.EscapeText(&b, []byte(k))
+			b.WriteString(`">`)
+			_ = xml.EscapeText(&b, []byte(le.Tags[k]))
+			b.WriteString("</tag>")
+		}
+		b.WriteString("</tags>")
+	}
+	if len(le.Metadata) > 0 {
+		b.WriteString("<metadata>")
+		writeXMLFields(&b, le.Metadata)
+		b.WriteString("</metadata>")
+	}
+	b.WriteString("</entry>")
+	return b.String(), nil
+}
+
+// writeXMLElement writes a simple element with escaped text content.
+func writeXMLElement(b *bytes.Buffer, name, text string) {
+	b.WriteString("<" + name + ">")
+	_ = xml.EscapeText(b, []byte(text))
+	b.WriteString("</" + name + ">")
+}
+
+// writeXMLFields writes the sorted entries of m as <field> elements.
+func writeXMLFields(b *bytes.Buffer, m map[string]interface{}) {
+	for _, k := range sortedKeys(m) {
+		b.WriteString(`<field key="`)
+		_ = xml.EscapeText(b, []byte(k))
+		b.WriteString(`">`)
+		writeXMLValue(b, normalizeValue(m[k]))
+		b.WriteString("</field>")
+	}
+}
+
+// writeXMLValue writes the content of a field or item element.
+func writeXMLValue(b *bytes.Buffer, v interface{}) {
+	switch val := v.(type) {
+	case nil:
+	case map[string]interface{}:
+		writeXMLFields(b, val)
+	case []interface{}:
+		for _, item := range val {
+			b.WriteString("<item>")
+			writeXMLValue(b, normalizeValue(item))
+			b.WriteString("</item>")
+		}
+	default:
+		_ = xml.EscapeText(b, []byte(fmt.Sprintf("%v", val)))
+	}
+}
+
+// RawFormatter writes only the message of each log entry, without any decoration.
+type RawFormatter struct{}
+
+// Format returns the message of the log entry.
+func (f *RawFormatter) Format(entry LogzEntry) (string, error) {
+	return entry.GetMessage(), nil
+}
@@ -0,0 +1,79 @@
+package core
+
+// Hook inspects an entry between its creation and the writers.
+// It returns the entry to pass on, which may be the received entry modified in place
+// or a new one, and false to drop it.
+type Hook func(LogzEntry) (LogzEntry, bool)
+
+// MultiHook is a hook that turns an entry into any number of entries.
+// Returning no entry drops it; returning several entries duplicates it, e.g. to send
+// a redacted copy alongside the original. Later hooks run on each returned entry.
+type MultiHook func(LogzEntry) []LogzEntry
+
+// AddHook appends a hook to the chain of the logger.
+// Hooks run in the order they were added, after the hooks of the parent loggers,
+// and apply to the child loggers derived from l.
+func (l *LogzCoreImpl) AddHook(hook Hook) {
+	if hook == nil {
+		return
+	}
+	l.AddMultiHook(func(entry LogzEntry) []LogzEntry {
+		if e, ok := hook(entry); ok && e != nil {
+			return []LogzEntry{e}
+		}
+		return nil
+	})
+}
+
+// AddMultiHook appends a hook that can drop or duplicate entries to the chain of the logger.
+func (l *LogzCoreImpl) AddMultiHook(hook MultiHook) {
+	if hook == nil {
+		return
+	}
+	l.hooksMu.Lock()
+	defer l.hooksMu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// hookChain returns the hooks of the parent loggers followed by the hooks of l.
+func (l *LogzCoreImpl) hookChain() []MultiHook {
+	var chain []MultiHook
+	if l.parent != nil {
+		chain = l.parent.hookChain()
+	}
+	l.hooksMu.RLock()
+	defer l.hooksMu.RUnlock()
+	return append(chain, l.hooks...)
+}
+
+// process delivers the entry; PANIC and FATAL entries then end with terminate,
+// even when the hooks dropped them.
+func (l *LogzCoreImpl) process(entry LogzEntry) {
+	level := entry.GetLevel()
+	l.deliver(entry)
+	if level == PANIC || level == FATAL {
+		l.terminate(entry)
+	}
+}
+
+// deliver samples the entry, runs it through the hook chain and dispatches the resulting entries.
+func (l *LogzCoreImpl) deliver(entry LogzEntry) {
+	if sampler := l.getSampler(); sampler != nil && !sampler.Sample(entry) {
+		return
+	}
+	entries := []LogzEntry{entry}
+	for _, hook := range l.hookChain() {
+		next := make([]LogzEntry, 0, len(entries))
+		for _, e := range entries {
+			next = append(next, hook(e)...)
+		}
+		entries = next
+	}
+
+	r := l.root()
+	for _, e := range entries {
+		if e != nil {
+			r.dispatch(e)
+		}
+	}
+}
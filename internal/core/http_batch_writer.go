@@ -0,0 +1,308 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BatchEncoder encodes a batch of entries into the body of one HTTP request.
+type BatchEncoder interface {
+	// ContentType returns the media type of the bodies.
+	ContentType() string
+	// Encode renders the entries, in order, as one body. Entries that cannot be encoded are
+	// left out of the body and reported in the error; the body is sent when it is not empty.
+	Encode(entries []LogzEntry) ([]byte, error)
+}
+
+// NDJSONEncoder encodes a batch as newline-delimited JSON, one entry per line.
+type NDJSONEncoder struct {
+	FieldMap *JSONFieldMap // Mapping of the entries; nil writes the default JSON layout.
+}
+
+// ContentType returns "application/x-ndjson".
+func (e *NDJSONEncoder) ContentType() string { return "application/x-ndjson" }
+
+// Encode writes each entry as a JSON object followed by a newline.
+func (e *NDJSONEncoder) Encode(entries []LogzEntry) ([]byte, error) {
+	f := &JSONFormatter{FieldMap: e.FieldMap}
+	var b bytes.Buffer
+	var errs []error
+	for _, entry := range entries {
+		line, err := f.Format(entry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), errors.Join(errs...)
+}
+
+// JSONArrayEncoder encodes a batch as a JSON array of entries.
+type JSONArrayEncoder struct {
+	FieldMap *JSONFieldMap // Mapping of the entries; nil writes the default JSON layout.
+}
+
+// ContentType returns "application/json".
+func (e *JSONArrayEncoder) ContentType() string { return "application/json" }
+
+// Encode writes the entries as the elements of a JSON array. It returns no body when
+// none of the entries can be encoded.
+func (e *JSONArrayEncoder) Encode(entries []LogzEntry) ([]byte, error) {
+	f := &JSONFormatter{FieldMap: e.FieldMap}
+	var b bytes.Buffer
+	var errs []error
+	b.WriteByte('[')
+	for _, entry := range entries {
+		obj, err := f.Format(entry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.WriteString(obj)
+	}
+	if b.Len() == 1 {
+		return nil, errors.Join(errs...)
+	}
+	b.WriteByte(']')
+	return b.Bytes(), errors.Join(errs...)
+}
+
+// SplunkHECEncoder encodes a batch for the Splunk HTTP Event Collector (/services/collector/event):
+// one envelope per entry, holding the time of the entry, the entry itself as the event and its
+// level and tags as indexed fields. Empty envelope settings are left to the token defaults.
+type SplunkHECEncoder struct {
+	Index      string        // Index of the events.
+	Source     string        // Source of the events; defaults to the source of each entry.
+	SourceType string        // Sourcetype of the events, e.g. "_json".
+	Host       string        // Host of the events; defaults to the hostname of each entry.
+	FieldMap   *JSONFieldMap // Mapping of the events; nil writes the default JSON layout.
+}
+
+// splunkEvent is the envelope of an event sent to the HTTP Event Collector.
+type splunkEvent struct {
+	Time       float64           `json:"time"`
+	Host       string            `json:"host,omitempty"`
+	Source     string            `json:"source,omitempty"`
+	SourceType string            `json:"sourcetype,omitempty"`
+	Index      string            `json:"index,omitempty"`
+	Event      json.RawMessage   `json:"event"`
+	Fields     map[string]string `json:"fields,omitempty"`
+}
+
+// ContentType returns "application/json".
+func (e *SplunkHECEncoder) ContentType() string { return "application/json" }
+
+// Encode writes one envelope per entry, separated by newlines.
+func (e *SplunkHECEncoder) Encode(entries []LogzEntry) ([]byte, error) {
+	f := &JSONFormatter{FieldMap: e.FieldMap}
+	var b bytes.Buffer
+	var errs []error
+	for _, entry := range entries {
+		event, err := f.Format(entry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		le := entryFields(entry)
+		if le.Timestamp.IsZero() {
+			le.Timestamp = time.Now()
+		}
+		env := splunkEvent{
+			Time:       float64(le.Timestamp.UnixMicro()) / 1e6,
+			Host:       e.Host,
+			Source:     e.Source,
+			SourceType: e.SourceType,
+			Index:      e.Index,
+			Event:      json.RawMessage(event),
+			Fields:     make(map[string]string, len(le.Tags)+1),
+		}
+		if env.Host == "" {
+			env.Host = le.Hostname
+		}
+		if env.Source == "" {
+			env.Source = le.Source
+		}
+		for k, v := range le.Tags {
+			env.Fields[k] = v
+		}
+		if le.Level != "" {
+			env.Fields["level"] = strings.ToLower(string(le.Level))
+		}
+		data, err := json.Marshal(env)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), errors.Join(errs...)
+}
+
+// HTTPBatchConfig holds the settings of an HTTPBatchWriter.
+type HTTPBatchConfig struct {
+	URL         string            // Endpoint receiving the batches.
+	Method      string            // HTTP method (default POST).
+	Encoder     BatchEncoder      // Encoder of the request bodies (default NDJSONEncoder).
+	Headers     map[string]string // Extra headers, e.g. {"Authorization": "Splunk <token>"} for the HTTP Event Collector.
+	BearerToken string            // Sent as "Authorization: Bearer <token>" when set.
+	Username    string            // Basic auth user, used when BearerToken is empty.
+	Password    string            // Basic auth password.
+	Gzip        bool              // Compress the request body with gzip.
+	BatchSize   int               // Maximum number of entries per request (default 100).
+	BatchWait   time.Duration     // Maximum age of a batch before it is sent (default 1s).
+	QueueSize   int               // Maximum number of entries buffered in memory (default 10000).
+	Overflow    OverflowPolicy    // Behavior when the buffer is full (default OverflowBlock).
+	Retry       RetryConfig       // Retries of the failed requests.
+	Timeout     time.Duration     // Timeout of each request (default 10s).
+	HTTPClient  *http.Client      // Client of the requests; defaults to a client with Timeout.
+}
+
+// HTTPBatchWriter is a LogWriter that buffers entries in a bounded queue and sends them in
+// batches to an HTTP endpoint, such as the Splunk HTTP Event Collector or any collector
+// accepting JSON arrays or NDJSON.
+//
+// Entries are sent in the background by an AsyncWriter, at the latest BatchWait after the
+// first entry of a batch. Requests failing with a network error, 429 or 5xx are retried with
+// backoff; a batch still failing after the retries is dropped and the error is logged.
+type HTTPBatchWriter struct {
+	async  *AsyncWriter
+	cancel context.CancelFunc // Cancels the requests of the client.
+}
+
+// httpBatchClient sends the batches; it is the output of the AsyncWriter of an HTTPBatchWriter.
+type httpBatchClient struct {
+	config HTTPBatchConfig
+	http   *http.Client
+	ctx    context.Context // Context of the requests, cancelled when Close gives up.
+}
+
+// NewHTTPBatchWriter creates an HTTPBatchWriter and starts its background worker.
+func NewHTTPBatchWriter(config HTTPBatchConfig) (*HTTPBatchWriter, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("http batch writer requires a URL")
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.Encoder == nil {
+		config.Encoder = &NDJSONEncoder{}
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.BatchWait <= 0 {
+		config.BatchWait = time.Second
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10000
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	config.Retry = config.Retry.withDefaults()
+
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &httpBatchClient{config: config, http: client, ctx: ctx}
+
+	return &HTTPBatchWriter{
+		async: NewAsyncWriter(c, AsyncWriterConfig{
+			QueueSize:     config.QueueSize,
+			Policy:        config.Overflow,
+			BatchSize:     config.BatchSize,
+			FlushInterval: config.BatchWait,
+		}),
+		cancel: cancel,
+	}, nil
+}
+
+// Write queues the entry for the next batch.
+func (w *HTTPBatchWriter) Write(entry any) error {
+	return w.async.Write(entry)
+}
+
+// Flush sends every queued entry, giving up when ctx is done.
+func (w *HTTPBatchWriter) Flush(ctx context.Context) error {
+	return w.async.Flush(ctx)
+}
+
+// Close sends the queued entries, retrying as configured by Retry, and stops the background worker.
+// Requests still pending after 5s are cancelled and their entries dropped.
+func (w *HTTPBatchWriter) Close() error {
+	return closePushWriter(w.async, w.cancel)
+}
+
+// Dropped returns the number of entries discarded because the buffer was full.
+func (w *HTTPBatchWriter) Dropped() uint64 {
+	return w.async.Dropped()
+}
+
+// Write sends a single entry.
+func (c *httpBatchClient) Write(entry any) error {
+	return c.WriteBatch([]any{entry})
+}
+
+// WriteBatch encodes the entries and sends them in one request. Entries that cannot be
+// encoded are reported in the returned error; the others are sent.
+func (c *httpBatchClient) WriteBatch(entries []any) error {
+	var errs []error
+	batch := make([]LogzEntry, 0, len(entries))
+	for _, entry := range entries {
+		switch v := entry.(type) {
+		case LogzEntry:
+			batch = append(batch, v)
+		case []byte:
+			batch = append(batch, NewLogEntry().WithLevel(INFO).WithMessage(string(v)))
+		default:
+			errs = append(errs, fmt.Errorf("unsupported log entry type: %T", entry))
+		}
+	}
+	if len(batch) == 0 {
+		return errors.Join(errs...)
+	}
+	data, err := c.config.Encoder.Encode(batch)
+	errs = append(errs, err)
+	if len(data) == 0 {
+		return errors.Join(errs...)
+	}
+	if c.config.Gzip {
+		if data, err = gzipBytes(data); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+	}
+
+	_, err = pushWithRetry(c.ctx, c.http, c.config.Retry, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, c.config.Method, c.config.URL, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", c.config.Encoder.ContentType())
+		if c.config.Gzip {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		setAuthHeader(req, c.config.BearerToken, c.config.Username, c.config.Password)
+		for k, v := range c.config.Headers {
+			req.Header.Set(k, v)
+		}
+		return req, nil
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("sending %d entries to %s: %w", len(batch), c.config.URL, err))
+	}
+	return errors.Join(errs...)
+}
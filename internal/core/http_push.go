@@ -0,0 +1,167 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxErrorBody bounds the part of a response body kept in a push error.
+const maxErrorBody = 512
+
+// pushCloseTimeout bounds the time the HTTP writers spend sending the queued entries on
+// Close; the requests still pending are then cancelled and their entries dropped.
+var pushCloseTimeout = 5 * time.Second
+
+// RetryConfig holds the retry settings of the writers pushing entries over HTTP.
+// Requests failing with a network error, 429 Too Many Requests or a 5xx status are
+// retried with an exponential backoff; a Retry-After header takes precedence, up to MaxBackoff.
+type RetryConfig struct {
+	MaxRetries int           // Retries after the first attempt (default 5); negative disables retries.
+	MinBackoff time.Duration // Wait before the first retry (default 500ms), doubled at each retry.
+	MaxBackoff time.Duration // Upper bound of the wait between retries (default 30s).
+}
+
+// withDefaults returns the configuration with its zero fields set to the defaults.
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxRetries == 0 {
+		c.MaxRetries = 5
+	}
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 30 * time.Second
+	}
+	if c.MaxBackoff < c.MinBackoff {
+		c.MaxBackoff = c.MinBackoff
+	}
+	return c
+}
+
+// backoff returns the wait before the given retry, starting at 1, with up to 20% of jitter.
+func (c RetryConfig) backoff(retry int) time.Duration {
+	d := c.MinBackoff
+	for i := 1; i < retry && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.MaxBackoff {
+		d = c.MaxBackoff
+	}
+	return d - time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+// PushError is returned by the HTTP writers when the endpoint rejects a request.
+type PushError struct {
+	StatusCode int    // Status of the last response.
+	Body       string // Beginning of the body of the last response.
+}
+
+func (e *PushError) Error() string {
+	return fmt.Sprintf("push rejected with status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryableStatus reports whether a request answered with status may succeed later.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// pushWithRetry sends the requests built by newRequest until one is accepted or fails with a
+// status that is not worth retrying. It returns the body of a 2xx response, or the last error,
+// which is a *PushError when the endpoint answered.
+func pushWithRetry(ctx context.Context, client *http.Client, retry RetryConfig, newRequest func(context.Context) (*http.Request, error)) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		body, wait, err := pushOnce(ctx, client, newRequest)
+		if err == nil {
+			return body, nil
+		}
+		if wait < 0 || attempt >= retry.MaxRetries {
+			return nil, err
+		}
+		if wait == 0 {
+			wait = retry.backoff(attempt + 1)
+		} else if wait > retry.MaxBackoff {
+			wait = retry.MaxBackoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+// pushOnce sends one request. On failure it returns how long to wait before retrying:
+// zero for the default backoff, the Retry-After delay when set, or -1 when the request
+// must not be retried.
+func pushOnce(ctx context.Context, client *http.Client, newRequest func(context.Context) (*http.Request, error)) ([]byte, time.Duration, error) {
+	req, err := newRequest(ctx)
+	if err != nil {
+		return nil, -1, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, -1, err
+		}
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, 0, err
+	}
+
+	pushErr := &PushError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(body))}
+	if len(pushErr.Body) > maxErrorBody {
+		pushErr.Body = pushErr.Body[:maxErrorBody]
+	}
+	if !retryableStatus(resp.StatusCode) {
+		return nil, -1, pushErr
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return nil, time.Duration(seconds) * time.Second, pushErr
+	}
+	return nil, 0, pushErr
+}
+
+// closePushWriter closes the AsyncWriter of an HTTP writer. When the queued entries are not
+// sent within pushCloseTimeout, cancel aborts the requests of the client, so that Close, and
+// a FATAL exit waiting on it, does not hang on an unreachable endpoint.
+func closePushWriter(async *AsyncWriter, cancel context.CancelFunc) error {
+	timer := time.AfterFunc(pushCloseTimeout, cancel)
+	defer timer.Stop()
+	defer cancel()
+	return async.Close()
+}
+
+// setAuthHeader sets a bearer token, or basic auth credentials when no token is given.
+func setAuthHeader(req *http.Request, token, username, password string) {
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case username != "":
+		req.SetBasicAuth(username, password)
+	}
+}
+
+// gzipBytes compresses data with gzip.
+func gzipBytes(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -1,5 +1,10 @@
 package core
 
+import (
+	"context"
+	"time"
+)
+
 // LogzLogger combines the existing core with the standard Go log methods.
 type LogzLogger interface {
 	LogzCore
@@ -12,6 +17,42 @@ type LogzLogger interface {
 	// SetLevel(VLevel interface{})
 	// The VLevel is an LogLevel type or string.
 	SetLevel(interface{})
+	// SetLevelSpec applies a level specification such as "info,db=debug,http.client=warn".
+	// Method signature:
+	// SetLevelSpec(spec string) error
+	SetLevelSpec(string) error
+	// SetModuleLevel sets the level of the loggers named after a module; an empty level removes it.
+	// Method signature:
+	// SetModuleLevel(module string, level LogLevel)
+	SetModuleLevel(string, LogLevel)
+	// ModuleLevels returns a copy of the module levels.
+	// Method signature:
+	// ModuleLevels() map[string]LogLevel
+	ModuleLevels() map[string]LogLevel
+	// EffectiveLevel returns the level resolved for the logger by longest-prefix matching on its name.
+	// Method signature:
+	// EffectiveLevel() LogLevel
+	EffectiveLevel() LogLevel
+	// ModuleLevel returns the effective level of the loggers named after a module.
+	// Method signature:
+	// ModuleLevel(module string) LogLevel
+	ModuleLevel(string) LogLevel
+	// SetLevelOverride sets a temporary level for a module ("" for the default level) that expires after the TTL.
+	// Method signature:
+	// SetLevelOverride(module string, level LogLevel, ttl time.Duration)
+	SetLevelOverride(string, LogLevel, time.Duration)
+	// ClearLevelOverride removes the temporary level of a module.
+	// Method signature:
+	// ClearLevelOverride(module string)
+	ClearLevelOverride(string)
+	// LevelOverrides returns the temporary levels that have not expired, by module.
+	// Method signature:
+	// LevelOverrides() map[string]LevelOverride
+	LevelOverrides() map[string]LevelOverride
+	// Loggers returns the module names of the loggers created with Named.
+	// Method signature:
+	// Loggers() []string
+	Loggers() []string
 }
 
 // LogzCore is the interface with the basic methods of the existing il.
@@ -21,6 +62,21 @@ type LogzCore interface {
 	// If the key is empty, it returns all VMetadata.
 	// Returns the value and a boolean indicating if the key exists.
 	SetMetadata(string, interface{})
+	// With returns a child logger carrying the given fields.
+	// Method signature:
+	// With(fields map[string]interface{}) LogzLogger
+	// The child shares writer and level with its parent and never mutates the parent fields.
+	With(map[string]interface{}) LogzLogger
+	// Named returns a child logger with a dotted name derived from the parent name.
+	// Method signature:
+	// Named(name string) LogzLogger
+	// The logger name is emitted as the source of every entry.
+	Named(string) LogzLogger
+	// WithError returns a child logger recording err, with its chain and concrete types, in every entry.
+	// Method signature:
+	// WithError(err error) LogzLogger
+	// e.g. logger.WithError(err).ErrorCtx("loading config", nil)
+	WithError(error) LogzLogger
 	// TraceCtx logs a trace message with context.
 	// Method signature:
 	// TraceCtx(message string, context map[string]interface{})
@@ -69,6 +125,169 @@ type LogzCore interface {
 	// The message is a string.
 	// The context is a map of key-value pairs.
 	FatalCtx(string, map[string]interface{})
+	// PanicCtx logs a panic message with context and panics with a *PanicError.
+	// Method signature:
+	// PanicCtx(message string, context map[string]interface{})
+	// The writers are flushed before panicking.
+	PanicCtx(string, map[string]interface{})
+	// Debug logs a debug message with typed fields.
+	// Method signature:
+	// Debug(message string, fields ...Field)
+	// Nothing is allocated when the level is disabled.
+	Debug(string, ...Field)
+	// Trace logs a trace message with typed fields.
+	// Method signature:
+	// Trace(message string, fields ...Field)
+	// Nothing is allocated when the level is disabled.
+	Trace(string, ...Field)
+	// Info logs an informational message with typed fields.
+	// Method signature:
+	// Info(message string, fields ...Field)
+	// Nothing is allocated when the level is disabled.
+	Info(string, ...Field)
+	// Notice logs a notice message with typed fields.
+	// Method signature:
+	// Notice(message string, fields ...Field)
+	// Nothing is allocated when the level is disabled.
+	Notice(string, ...Field)
+	// Success logs a success message with typed fields.
+	// Method signature:
+	// Success(message string, fields ...Field)
+	// Nothing is allocated when the level is disabled.
+	Success(string, ...Field)
+	// Warn logs a warning message with typed fields.
+	// Method signature:
+	// Warn(message string, fields ...Field)
+	// Nothing is allocated when the level is disabled.
+	Warn(string, ...Field)
+	// Error logs an error message with typed fields.
+	// Method signature:
+	// Error(message string, fields ...Field)
+	// Nothing is allocated when the level is disabled.
+	Error(string, ...Field)
+	// PanicFields logs a panic message with typed fields and panics with a *PanicError.
+	// It is not named Panic so as not to clash with log.Logger.Panic in logger.Logger.
+	// Method signature:
+	// PanicFields(message string, fields ...Field)
+	PanicFields(string, ...Field)
+	// FatalFields logs a fatal message with typed fields and exits the application.
+	// It is not named Fatal so as not to clash with log.Logger.Fatal in logger.Logger.
+	// Method signature:
+	// FatalFields(message string, fields ...Field)
+	FatalFields(string, ...Field)
+	// TraceContext logs a trace message using the request-scoped values of a context.Context.
+	// Method signature:
+	// TraceContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	TraceContext(context.Context, string, ...map[string]interface{})
+	// NoticeContext logs a notice message using the request-scoped values of a context.Context.
+	// Method signature:
+	// NoticeContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	NoticeContext(context.Context, string, ...map[string]interface{})
+	// SuccessContext logs a success message using the request-scoped values of a context.Context.
+	// Method signature:
+	// SuccessContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	SuccessContext(context.Context, string, ...map[string]interface{})
+	// DebugContext logs a debug message using the request-scoped values of a context.Context.
+	// Method signature:
+	// DebugContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	DebugContext(context.Context, string, ...map[string]interface{})
+	// InfoContext logs an informational message using the request-scoped values of a context.Context.
+	// Method signature:
+	// InfoContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	InfoContext(context.Context, string, ...map[string]interface{})
+	// WarnContext logs a warning message using the request-scoped values of a context.Context.
+	// Method signature:
+	// WarnContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	WarnContext(context.Context, string, ...map[string]interface{})
+	// ErrorContext logs an error message using the request-scoped values of a context.Context.
+	// Method signature:
+	// ErrorContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	ErrorContext(context.Context, string, ...map[string]interface{})
+	// FatalContext logs a fatal message using the request-scoped values of a context.Context and exits the application.
+	// Method signature:
+	// FatalContext(ctx context.Context, message string, fields ...map[string]interface{})
+	// The request ID, trace ID and span ID stored in ctx are added to the entry,
+	// and a scoped logger stored in ctx receives the entry instead.
+	FatalContext(context.Context, string, ...map[string]interface{})
+	// PanicContext logs a panic message using the request-scoped values of a context.Context and panics with a *PanicError.
+	// Method signature:
+	// PanicContext(ctx context.Context, message string, fields ...map[string]interface{})
+	PanicContext(context.Context, string, ...map[string]interface{})
+	// SetExitFunc sets the function called by FATAL entries to terminate the process; nil restores os.Exit.
+	// Method signature:
+	// SetExitFunc(exit func(code int))
+	SetExitFunc(func(int))
+	// SetFatalMode sets whether FATAL entries exit the process or panic.
+	// Method signature:
+	// SetFatalMode(mode FatalMode)
+	SetFatalMode(FatalMode)
+	// SetRecovery sets how the panics recovered by RecoverAndLog and Go are handled.
+	// Method signature:
+	// SetRecovery(config RecoveryConfig)
+	SetRecovery(RecoveryConfig)
+	// RecoverAndLog recovers a panic, logs it with its stack and applies the recovery policy.
+	// Method signature:
+	// RecoverAndLog()
+	// It must be deferred directly: defer logger.RecoverAndLog().
+	RecoverAndLog()
+	// HandlePanic logs a value returned by recover and applies the recovery policy.
+	// Method signature:
+	// HandlePanic(value interface{})
+	HandlePanic(interface{})
+	// Go runs a function in a new goroutine whose panics are handled by RecoverAndLog.
+	// Method signature:
+	// Go(f func())
+	Go(func())
+	// Flush flushes the writer and the notifiers that buffer entries.
+	// Method signature:
+	// Flush(ctx context.Context) error
+	Flush(context.Context) error
+	// Close flushes and closes the writer and the notifiers.
+	// Method signature:
+	// Close() error
+	// FATAL entries call it before exiting.
+	Close() error
+	// Enabled reports whether entries of the given level are logged.
+	// Method signature:
+	// Enabled(level LogLevel) bool
+	Enabled(LogLevel) bool
+	// Emit routes a prebuilt entry through the writers, notifiers and metrics.
+	// Method signature:
+	// Emit(entry LogzEntry)
+	// Missing source and metadata keys are completed from the logger.
+	Emit(LogzEntry)
+	// AddHook appends a hook that can rewrite or drop entries before they are written.
+	// Method signature:
+	// AddHook(hook Hook)
+	// Hooks are inherited by the child loggers created with With and Named.
+	AddHook(Hook)
+	// AddMultiHook appends a hook that can also duplicate entries.
+	// Method signature:
+	// AddMultiHook(hook MultiHook)
+	AddMultiHook(MultiHook)
+	// SetSampler sets the sampler limiting repeated entries; nil disables sampling.
+	// Method signature:
+	// SetSampler(sampler *Sampler)
+	SetSampler(*Sampler)
+	// SetStackTraceLevel sets the lowest level at which entries carrying an error capture the call stack.
+	// Method signature:
+	// SetStackTraceLevel(level LogLevel)
+	// An empty level disables stack capture.
+	SetStackTraceLevel(LogLevel)
 	// GetWriter returns the current VWriter.
 	// Method signature:
 	// GetWriter() interface{}
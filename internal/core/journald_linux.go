@@ -0,0 +1,77 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// isMessageTooLarge reports whether a datagram was rejected for its size.
+func isMessageTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// sendJournalFile writes data to a sealed memory file and passes its descriptor to journald,
+// which reads the entry from it. Kernels without memfd_create use an unlinked file in /dev/shm.
+func sendJournalFile(conn *net.UnixConn, addr *net.UnixAddr, data []byte) error {
+	file, err := journalMemfd(data)
+	if err != nil {
+		return fmt.Errorf("passing large entry to journald: %w", err)
+	}
+	defer file.Close()
+	_, _, err = conn.WriteMsgUnix(nil, unix.UnixRights(int(file.Fd())), addr)
+	return err
+}
+
+// journalMemfd returns a read-only file holding data.
+func journalMemfd(data []byte) (*os.File, error) {
+	fd, err := unix.MemfdCreate("logz-journal", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return journalTempFile(data)
+	}
+	file := os.NewFile(uintptr(fd), "logz-journal")
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+	seals := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+	if _, err := unix.FcntlInt(file.Fd(), unix.F_ADD_SEALS, seals); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// journalTempFile returns an unlinked file in /dev/shm holding data.
+func journalTempFile(data []byte) (*os.File, error) {
+	file, err := os.CreateTemp("/dev/shm", "logz-journal-")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// JournalStreamConnected reports whether the standard error of the process is connected to
+// journald, as systemd announces in JOURNAL_STREAM for the services it starts.
+func JournalStreamConnected() bool {
+	dev, ino, ok := strings.Cut(os.Getenv("JOURNAL_STREAM"), ":")
+	if !ok {
+		return false
+	}
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(os.Stderr.Fd()), &st); err != nil {
+		return false
+	}
+	return dev == strconv.FormatUint(uint64(st.Dev), 10) && ino == strconv.FormatUint(st.Ino, 10)
+}
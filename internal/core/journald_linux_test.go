@@ -0,0 +1,98 @@
+package core
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestJournaldWriter(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.socket")
+	journal, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listening on %s: %v", socket, err)
+	}
+	defer journal.Close()
+
+	w, err := NewJournaldWriter(JournaldConfig{Socket: socket, Identifier: "svc", Fields: map[string]string{"role": "api"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	entry := NewLogEntry().
+		WithLevel(WARN).
+		WithMessage("slow\nquery").
+		WithSource("db").
+		WithCaller("store/users.go:42 github.com/acme/store.(*Users).Find").
+		AddMetadata("user.id", 7).
+		AddMetadata("request", map[string]interface{}{"path": "/users"}).
+		AddMetadata("message", "shadowed").
+		AddMetadata("priority", 0).
+		AddMetadata("logz_message", "taken").
+		AddTag("syslog_identifier", "spoofed")
+	entry.WithTimestamp(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	if err := w.Write(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	journal.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := journal.Read(buf)
+	if err != nil {
+		t.Fatalf("reading datagram: %v", err)
+	}
+	want := "MESSAGE\n\x0a\x00\x00\x00\x00\x00\x00\x00slow\nquery\n" +
+		"PRIORITY=4\nSYSLOG_IDENTIFIER=svc\nSYSLOG_TIMESTAMP=2026-10-16T12:00:00Z\nLOGZ_LEVEL=WARN\nLOGZ_SOURCE=db\n" +
+		"CODE_FILE=store/users.go\nCODE_LINE=42\nCODE_FUNC=github.com/acme/store.(*Users).Find\n" +
+		"ROLE=api\nTAG_SYSLOG_IDENTIFIER=spoofed\n" +
+		"LOGZ_MESSAGE=taken\nLOGZ2_MESSAGE=shadowed\nLOGZ_PRIORITY=0\nREQUEST_PATH=/users\nUSER_ID=7\n"
+	if got := string(buf[:n]); got != want {
+		t.Errorf("unexpected datagram:\n got %q\nwant %q", got, want)
+	}
+
+	// An entry larger than a datagram is passed in a memory file.
+	large := strings.Repeat("x", 1<<20)
+	if err := w.Write(NewLogEntry().WithLevel(INFO).WithMessage(large)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	oob := make([]byte, 64)
+	journal.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, oobn, _, _, err := journal.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("reading descriptor: %v", err)
+	}
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("expected one control message, got %d (%v)", len(msgs), err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("expected one descriptor, got %v (%v)", fds, err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "journal")
+	defer f.Close()
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, 2<<20))
+	if err != nil || !strings.HasPrefix(string(data), "MESSAGE="+large+"\nPRIORITY=6\n") {
+		t.Errorf("unexpected memory file content (%d bytes, %v)", len(data), err)
+	}
+
+	for a, pa := range journalPriorities {
+		for b, pb := range journalPriorities {
+			if logLevels[a] > logLevels[b] && pa > pb {
+				t.Errorf("expected %s (%d) to be at least as severe as %s (%d)", a, pa, b, pb)
+			}
+		}
+	}
+	if got := journalFieldName("_9-lives.count"); got != "F_9_LIVES_COUNT" {
+		t.Errorf("unexpected field name %q", got)
+	}
+	if _, err := NewJournaldWriter(JournaldConfig{Socket: filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("expected an error without a journald socket")
+	}
+}
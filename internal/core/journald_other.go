@@ -0,0 +1,24 @@
+//go:build !linux
+
+package core
+
+import (
+	"errors"
+	"net"
+)
+
+// isMessageTooLarge reports whether a datagram was rejected for its size.
+func isMessageTooLarge(error) bool {
+	return false
+}
+
+// sendJournalFile is only supported on Linux, where journald runs.
+func sendJournalFile(*net.UnixConn, *net.UnixAddr, []byte) error {
+	return errors.New("passing large entries to journald requires Linux")
+}
+
+// JournalStreamConnected reports whether the standard error of the process is connected to
+// journald; it is always false outside Linux.
+func JournalStreamConnected() bool {
+	return false
+}
@@ -0,0 +1,269 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultJournalSocket is the native protocol socket of systemd-journald.
+const defaultJournalSocket = "/run/systemd/journal/socket"
+
+// journalPriorities maps the logz levels to the syslog priorities stored in PRIORITY.
+var journalPriorities = map[LogLevel]int{
+	DEBUG:   7,
+	TRACE:   7,
+	INFO:    6,
+	NOTICE:  5,
+	SUCCESS: 5,
+	WARN:    4,
+	ERROR:   3,
+	PANIC:   2,
+	FATAL:   1,
+}
+
+// journalReservedFields are the fields journald interprets; metadata and tags never use them.
+var journalReservedFields = map[string]bool{
+	"MESSAGE": true, "MESSAGE_ID": true, "PRIORITY": true, "CODE_FILE": true, "CODE_LINE": true,
+	"CODE_FUNC": true, "ERRNO": true, "INVOCATION_ID": true, "USER_INVOCATION_ID": true,
+	"SYSLOG_FACILITY": true, "SYSLOG_IDENTIFIER": true, "SYSLOG_PID": true, "SYSLOG_TIMESTAMP": true,
+	"SYSLOG_RAW": true, "DOCUMENTATION": true, "TID": true, "UNIT": true, "USER_UNIT": true,
+}
+
+// JournaldConfig holds the settings of a JournaldWriter.
+type JournaldConfig struct {
+	Socket     string            // Path of the journald socket (default /run/systemd/journal/socket).
+	Identifier string            // SYSLOG_IDENTIFIER of the entries; defaults to the name of the executable.
+	Fields     map[string]string // Constant fields added to every entry, e.g. {"UNIT_ROLE": "api"}.
+}
+
+// JournaldWriter is a LogWriter that sends entries to systemd-journald with its native protocol,
+// so that `journalctl -o json` shows them as structured records.
+//
+// Every entry carries MESSAGE, PRIORITY, SYSLOG_IDENTIFIER, LOGZ_LEVEL and, when set, the
+// source (LOGZ_SOURCE), the trace ID (TRACE_ID), the error (ERROR, ERROR_TYPE) and the caller
+// split into CODE_FILE, CODE_LINE and CODE_FUNC. Metadata and tags become uppercase fields:
+// characters other than A-Z, 0-9 and '_' are replaced by '_', nested maps are flattened with
+// '_' and slices are written as JSON. A metadata or tag field whose name is interpreted by
+// journald or already used by the entry is prefixed with LOGZ_ (LOGZ2_, LOGZ3_... when that
+// is taken too), so that "message" becomes LOGZ_MESSAGE. Entries too large for a datagram are passed to journald
+// in a sealed memory file, as journald expects.
+type JournaldWriter struct {
+	config JournaldConfig
+	addr   *net.UnixAddr
+
+	mu     sync.Mutex
+	conn   *net.UnixConn
+	closed bool
+}
+
+// NewJournaldWriter creates a JournaldWriter bound to the journald socket.
+// It fails when the socket does not exist, i.e. when journald is not running.
+func NewJournaldWriter(config JournaldConfig) (*JournaldWriter, error) {
+	if config.Socket == "" {
+		config.Socket = defaultJournalSocket
+	}
+	if config.Identifier == "" {
+		config.Identifier = filepath.Base(os.Args[0])
+	}
+	if _, err := os.Stat(config.Socket); err != nil {
+		return nil, fmt.Errorf("journald socket unavailable: %w", err)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("opening journald connection: %w", err)
+	}
+	return &JournaldWriter{
+		config: config,
+		addr:   &net.UnixAddr{Name: config.Socket, Net: "unixgram"},
+		conn:   conn,
+	}, nil
+}
+
+// Write encodes the entry with the journald native protocol and sends it.
+func (w *JournaldWriter) Write(entry any) error {
+	var e LogzEntry
+	switch v := entry.(type) {
+	case LogzEntry:
+		e = v
+	case []byte:
+		e = NewLogEntry().WithLevel(INFO).WithMessage(string(v))
+	default:
+		return fmt.Errorf("unsupported log entry type: %T", entry)
+	}
+	data := w.encode(e)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	_, err := w.conn.WriteToUnix(data, w.addr)
+	if err == nil || !isMessageTooLarge(err) {
+		return err
+	}
+	return sendJournalFile(w.conn, w.addr, data)
+}
+
+// Close closes the connection to journald.
+func (w *JournaldWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.conn.Close()
+}
+
+// encode renders the fields of the entry in the journald native format.
+func (w *JournaldWriter) encode(entry LogzEntry) []byte {
+	le := entryFields(entry)
+	priority, ok := journalPriorities[le.Level]
+	if !ok {
+		priority = journalPriorities[INFO]
+	}
+
+	e := &journalEncoder{seen: make(map[string]bool)}
+	e.field("MESSAGE", le.Message)
+	e.field("PRIORITY", strconv.Itoa(priority))
+	e.field("SYSLOG_IDENTIFIER", w.config.Identifier)
+	if !le.Timestamp.IsZero() {
+		e.field("SYSLOG_TIMESTAMP", le.Timestamp.Format(time.RFC3339Nano))
+	}
+	if le.Level != "" {
+		e.field("LOGZ_LEVEL", string(le.Level))
+	}
+	if le.Source != "" {
+		e.field("LOGZ_SOURCE", le.Source)
+	}
+	if le.TraceID != "" {
+		e.field("TRACE_ID", le.TraceID)
+	}
+	if le.Error != nil {
+		e.field("ERROR", le.Error.String())
+		e.field("ERROR_TYPE", le.Error.Type)
+	}
+	if file, line, function, ok := splitCaller(le.Caller); ok {
+		e.field("CODE_FILE", file)
+		e.field("CODE_LINE", line)
+		e.field("CODE_FUNC", function)
+	}
+	// The constant fields are set by the application and may use the journald fields, e.g. MESSAGE_ID.
+	for _, k := range sortedKeys(w.config.Fields) {
+		if name := journalFieldName(k); !e.seen[name] {
+			e.field(name, w.config.Fields[k])
+		}
+	}
+	for _, k := range sortedKeys(le.Tags) {
+		e.userField(journalFieldName("TAG_"+k), le.Tags[k])
+	}
+	for _, k := range sortedKeys(le.Metadata) {
+		e.value(journalFieldName(k), normalizeValue(le.Metadata[k]))
+	}
+	return e.b.Bytes()
+}
+
+// journalEncoder renders the fields of an entry, keeping track of the names already used.
+type journalEncoder struct {
+	b    bytes.Buffer
+	seen map[string]bool
+}
+
+// field writes a field under its name.
+func (e *journalEncoder) field(name, value string) {
+	e.seen[name] = true
+	writeJournalField(&e.b, name, value)
+}
+
+// userField writes a metadata or tag field, renaming it when its name is reserved or taken.
+func (e *journalEncoder) userField(name, value string) {
+	if journalReservedFields[name] || e.seen[name] {
+		renamed := journalFieldName("LOGZ_" + name)
+		for i := 2; journalReservedFields[renamed] || e.seen[renamed]; i++ {
+			renamed = journalFieldName(fmt.Sprintf("LOGZ%d_%s", i, name))
+		}
+		name = renamed
+	}
+	e.field(name, value)
+}
+
+// value writes a metadata value, flattening nested maps into '_'-joined names.
+func (e *journalEncoder) value(name string, v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(val) {
+			e.value(journalFieldName(name+"_"+k), normalizeValue(val[k]))
+		}
+	case []interface{}:
+		data, err := json.Marshal(val)
+		if err != nil {
+			e.userField(name, fmt.Sprintf("%v", val))
+			return
+		}
+		e.userField(name, string(data))
+	case nil:
+		e.userField(name, "")
+	default:
+		e.userField(name, fmt.Sprintf("%v", val))
+	}
+}
+
+// writeJournalField writes one field: "NAME=value\n", or, when the value contains a newline,
+// "NAME\n" followed by the length of the value as a little-endian uint64, the value and "\n".
+func writeJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.ContainsRune(value, '\n') {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	b.Write(size[:])
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalFieldName converts a key into a valid journal field name: uppercase letters, digits
+// and '_', not starting with '_' or a digit, at most 64 characters.
+func journalFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	// Fields starting with '_' are trusted fields, set by journald only.
+	s := strings.TrimLeft(string(name), "_")
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "F_" + s
+	}
+	if len(s) > 64 {
+		s = s[:64]
+	}
+	return s
+}
+
+// splitCaller splits a caller recorded as "file:line function" into its parts.
+func splitCaller(caller string) (file, line, function string, ok bool) {
+	location, function, found := strings.Cut(caller, " ")
+	if !found {
+		return "", "", "", false
+	}
+	i := strings.LastIndexByte(location, ':')
+	if i < 0 {
+		return "", "", "", false
+	}
+	return location[:i], location[i+1:], function, true
+}
@@ -0,0 +1,281 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Timestamp layouts of a JSONFieldMap that write the time as a number instead of a string.
+const (
+	TimeFormatUnix      = "unix"    // Seconds since the Unix epoch.
+	TimeFormatUnixMilli = "unix_ms" // Milliseconds since the Unix epoch.
+	TimeFormatUnixNano  = "unix_ns" // Nanoseconds since the Unix epoch.
+)
+
+// JSONFieldMap configures the keys and value layouts written by a JSONFormatter.
+//
+// An empty key uses the default name shown next to each field and a key set to "-"
+// omits the field. Keys are written as given, so dotted names such as "log.level"
+// are kept flat.
+type JSONFieldMap struct {
+	TimestampKey string // Default "timestamp".
+	LevelKey     string // Default "level".
+	MessageKey   string // Default "message".
+	SourceKey    string // Default "source".
+	ContextKey   string // Default "context".
+	SeverityKey  string // Default "severity".
+	TraceIDKey   string // Default "trace_id".
+	CallerKey    string // Default "caller".
+	HostnameKey  string // Default "hostname".
+	PIDKey       string // Default "pid".
+	TagsKey      string // Default "tags".
+	ErrorKey     string // Default "error".
+
+	// MetadataKey nests the metadata under the given key. When empty the metadata is
+	// flattened into the top-level object; keys clashing with an entry field are
+	// then prefixed with "meta." until they are unique.
+	MetadataKey string
+
+	// TimeFormat is a time layout or one of the TimeFormatUnix* constants. Default time.RFC3339Nano.
+	TimeFormat string
+	// LevelNames maps the logz levels to the names written in the level field.
+	// Levels missing from the map are written as is.
+	LevelNames map[LogLevel]string
+	// StaticFields are added to every entry, e.g. the schema version expected by the platform.
+	StaticFields map[string]interface{}
+}
+
+// ECSFieldMap returns the mapping for the Elastic Common Schema (ecs-logging).
+func ECSFieldMap() JSONFieldMap {
+	return JSONFieldMap{
+		TimestampKey: "@timestamp",
+		LevelKey:     "log.level",
+		MessageKey:   "message",
+		SourceKey:    "log.logger",
+		SeverityKey:  "event.severity",
+		TraceIDKey:   "trace.id",
+		CallerKey:    "log.origin.function",
+		HostnameKey:  "host.hostname",
+		PIDKey:       "process.pid",
+		TagsKey:      "labels",
+		TimeFormat:   "2006-01-02T15:04:05.000Z07:00",
+		LevelNames: map[LogLevel]string{
+			DEBUG:   "debug",
+			TRACE:   "trace",
+			INFO:    "info",
+			NOTICE:  "notice",
+			SUCCESS: "info",
+			WARN:    "warn",
+			ERROR:   "error",
+			PANIC:   "panic",
+			FATAL:   "fatal",
+		},
+		StaticFields: map[string]interface{}{"ecs.version": "8.11.0"},
+	}
+}
+
+// GCPFieldMap returns the mapping for Google Cloud Logging structured logs.
+func GCPFieldMap() JSONFieldMap {
+	return JSONFieldMap{
+		TimestampKey: "timestamp",
+		LevelKey:     "severity",
+		MessageKey:   "message",
+		SourceKey:    "logger",
+		SeverityKey:  "-",
+		TraceIDKey:   "logging.googleapis.com/trace",
+		CallerKey:    "caller",
+		TagsKey:      "logging.googleapis.com/labels",
+		LevelNames: map[LogLevel]string{
+			DEBUG:   "DEBUG",
+			TRACE:   "DEBUG",
+			INFO:    "INFO",
+			NOTICE:  "NOTICE",
+			SUCCESS: "INFO",
+			WARN:    "WARNING",
+			ERROR:   "ERROR",
+			PANIC:   "CRITICAL",
+			FATAL:   "ALERT",
+		},
+	}
+}
+
+// DatadogFieldMap returns the mapping for the Datadog reserved and standard attributes.
+func DatadogFieldMap() JSONFieldMap {
+	return JSONFieldMap{
+		TimestampKey: "timestamp",
+		LevelKey:     "status",
+		MessageKey:   "message",
+		SourceKey:    "logger.name",
+		SeverityKey:  "-",
+		TraceIDKey:   "dd.trace_id",
+		CallerKey:    "logger.method_name",
+		HostnameKey:  "host",
+		PIDKey:       "process.pid",
+		TimeFormat:   TimeFormatUnixMilli,
+		LevelNames: map[LogLevel]string{
+			DEBUG:   "debug",
+			TRACE:   "debug",
+			INFO:    "info",
+			NOTICE:  "notice",
+			SUCCESS: "info",
+			WARN:    "warn",
+			ERROR:   "error",
+			PANIC:   "critical",
+			FATAL:   "critical",
+		},
+	}
+}
+
+// JSONFieldMapPreset returns the mapping registered under the given name: ecs, gcp or datadog.
+func JSONFieldMapPreset(name string) (JSONFieldMap, bool) {
+	switch strings.ToLower(name) {
+	case "ecs", "elastic":
+		return ECSFieldMap(), true
+	case "gcp", "stackdriver":
+		return GCPFieldMap(), true
+	case "datadog", "dd":
+		return DatadogFieldMap(), true
+	default:
+		return JSONFieldMap{}, false
+	}
+}
+
+// key returns the configured key, or def when it is empty.
+func (m *JSONFieldMap) key(key, def string) string {
+	if key == "" {
+		return def
+	}
+	return key
+}
+
+// format writes the entry as a JSON object following the mapping.
+func (m *JSONFieldMap) format(entry LogzEntry) (string, error) {
+	le := entryFields(entry)
+	obj := &jsonObject{seen: make(map[string]bool)}
+
+	obj.add(m.key(m.TimestampKey, "timestamp"), m.timestamp(le.Timestamp))
+	obj.add(m.key(m.LevelKey, "level"), m.levelName(le.Level))
+	obj.add(m.key(m.MessageKey, "message"), le.Message)
+	for _, kv := range []struct{ key, value string }{
+		{m.key(m.SourceKey, "source"), le.Source},
+		{m.key(m.ContextKey, "context"), le.Context},
+		{m.key(m.TraceIDKey, "trace_id"), le.TraceID},
+		{m.key(m.CallerKey, "caller"), le.Caller},
+		{m.key(m.HostnameKey, "hostname"), le.Hostname},
+	} {
+		if kv.value != "" {
+			obj.add(kv.key, kv.value)
+		}
+	}
+	if le.ProcessID != 0 {
+		obj.add(m.key(m.PIDKey, "pid"), le.ProcessID)
+	}
+	severity := le.Severity
+	if severity == 0 {
+		severity = logLevels[le.Level]
+	}
+	obj.add(m.key(m.SeverityKey, "severity"), severity)
+	if len(le.Tags) > 0 {
+		obj.add(m.key(m.TagsKey, "tags"), le.Tags)
+	}
+	if le.Error != nil {
+		obj.add(m.key(m.ErrorKey, "error"), le.Error)
+	}
+	for _, k := range sortedKeys(m.StaticFields) {
+		obj.add(k, m.StaticFields[k])
+	}
+
+	if len(le.Metadata) > 0 {
+		if m.MetadataKey != "" {
+			metadata := make(map[string]interface{}, len(le.Metadata))
+			for k, v := range le.Metadata {
+				metadata[k] = jsonFieldValue(v)
+			}
+			obj.add(m.MetadataKey, metadata)
+		} else {
+			for _, k := range sortedKeys(le.Metadata) {
+				key := k
+				for obj.seen[key] {
+					key = "meta." + key
+				}
+				obj.add(key, jsonFieldValue(le.Metadata[k]))
+			}
+		}
+	}
+	return obj.close()
+}
+
+// timestamp renders t with the configured layout.
+func (m *JSONFieldMap) timestamp(t time.Time) interface{} {
+	switch m.TimeFormat {
+	case "":
+		return t.Format(time.RFC3339Nano)
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMilli:
+		return t.UnixMilli()
+	case TimeFormatUnixNano:
+		return t.UnixNano()
+	default:
+		return t.Format(m.TimeFormat)
+	}
+}
+
+// levelName returns the name written for the level.
+func (m *JSONFieldMap) levelName(level LogLevel) string {
+	if name, ok := m.LevelNames[level]; ok {
+		return name
+	}
+	return string(level)
+}
+
+// jsonFieldValue converts values that encoding/json cannot render meaningfully.
+func jsonFieldValue(v interface{}) interface{} {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return v
+}
+
+// jsonObject writes the members of a JSON object in insertion order.
+type jsonObject struct {
+	buf  bytes.Buffer
+	seen map[string]bool
+	err  error
+}
+
+// add appends a member, skipping omitted ("-") and already written keys.
+func (o *jsonObject) add(key string, value interface{}) {
+	if o.err != nil || key == "-" || o.seen[key] {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		o.err = err
+		return
+	}
+	if o.buf.Len() == 0 {
+		o.buf.WriteByte('{')
+	} else {
+		o.buf.WriteByte(',')
+	}
+	name, _ := json.Marshal(key)
+	o.buf.Write(name)
+	o.buf.WriteByte(':')
+	o.buf.Write(data)
+	o.seen[key] = true
+}
+
+// close terminates the object and returns it.
+func (o *jsonObject) close() (string, error) {
+	if o.err != nil {
+		return "", o.err
+	}
+	if o.buf.Len() == 0 {
+		return "{}", nil
+	}
+	o.buf.WriteByte('}')
+	return o.buf.String(), nil
+}
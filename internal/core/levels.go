@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LevelOverride is a temporary level that expires after a TTL.
+type LevelOverride struct {
+	Level     LogLevel  `json:"level"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ParseLevel converts a case-insensitive level name into a LogLevel.
+func ParseLevel(name string) (LogLevel, error) {
+	level := LogLevel(strings.ToUpper(strings.TrimSpace(name)))
+	if _, ok := logLevels[level]; !ok {
+		return "", fmt.Errorf("invalid log level: %q", name)
+	}
+	return level, nil
+}
+
+// ParseLevelSpec parses a level specification such as "info,db=debug,http.client=warn".
+// A bare level sets the default level; "module=level" pairs set the level of a module.
+// The default level is empty when the specification does not set it.
+func ParseLevelSpec(spec string) (LogLevel, map[string]LogLevel, error) {
+	var def LogLevel
+	modules := make(map[string]LogLevel)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		module, name, isModule := strings.Cut(part, "=")
+		if !isModule {
+			name = module
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return "", nil, err
+		}
+		if !isModule {
+			def = level
+			continue
+		}
+		module = strings.TrimSpace(module)
+		if module == "" {
+			return "", nil, fmt.Errorf("missing module name in %q", part)
+		}
+		modules[module] = level
+	}
+	return def, modules, nil
+}
+
+// SetLevelSpec applies a level specification such as "info,db=debug" (see ParseLevelSpec).
+// The module levels of the specification replace the ones set before.
+func (l *LogzCoreImpl) SetLevelSpec(spec string) error {
+	def, modules, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	r := l.root()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	if def != "" {
+		r.VLevel = def
+	}
+	r.moduleLevels = modules
+	return nil
+}
+
+// SetModuleLevel sets the level of the loggers named after module, e.g. "db" or "http.client".
+// Module names are the dotted names given to Named, without the prefix of the root logger.
+// An empty level removes the setting of the module.
+func (l *LogzCoreImpl) SetModuleLevel(module string, level LogLevel) {
+	r := l.root()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	if level == "" {
+		delete(r.moduleLevels, module)
+		return
+	}
+	if r.moduleLevels == nil {
+		r.moduleLevels = make(map[string]LogLevel)
+	}
+	r.moduleLevels[module] = level
+}
+
+// ModuleLevels returns a copy of the module levels.
+func (l *LogzCoreImpl) ModuleLevels() map[string]LogLevel {
+	r := l.root()
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	levels := make(map[string]LogLevel, len(r.moduleLevels))
+	for k, v := range r.moduleLevels {
+		levels[k] = v
+	}
+	return levels
+}
+
+// SetLevelOverride sets a temporary level for module, or for the default level when module
+// is empty. The override takes precedence over the configured level until the TTL elapses.
+func (l *LogzCoreImpl) SetLevelOverride(module string, level LogLevel, ttl time.Duration) {
+	r := l.root()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	if r.levelOverrides == nil {
+		r.levelOverrides = make(map[string]LevelOverride)
+	}
+	r.levelOverrides[module] = LevelOverride{Level: level, ExpiresAt: time.Now().Add(ttl)}
+}
+
+// ClearLevelOverride removes the temporary level of module, or of the default level when module is empty.
+func (l *LogzCoreImpl) ClearLevelOverride(module string) {
+	r := l.root()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	delete(r.levelOverrides, module)
+}
+
+// LevelOverrides returns the temporary levels that have not expired, by module.
+// The override of the default level is stored under the empty module name.
+func (l *LogzCoreImpl) LevelOverrides() map[string]LevelOverride {
+	r := l.root()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	now := time.Now()
+	overrides := make(map[string]LevelOverride, len(r.levelOverrides))
+	for module, o := range r.levelOverrides {
+		if now.After(o.ExpiresAt) {
+			delete(r.levelOverrides, module)
+			continue
+		}
+		overrides[module] = o
+	}
+	return overrides
+}
+
+// Loggers returns the sorted module names of the loggers created with Named.
+func (l *LogzCoreImpl) Loggers() []string {
+	r := l.root()
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	names := make([]string, 0, len(r.loggers))
+	for name := range r.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ModuleLevel returns the effective level of the loggers named after module.
+func (l *LogzCoreImpl) ModuleLevel(module string) LogLevel {
+	r := l.root()
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	return r.levelFor(module)
+}
+
+// EffectiveLevel returns the level of the logger: the level of the longest module
+// matching its name, or the default level when none matches.
+func (l *LogzCoreImpl) EffectiveLevel() LogLevel {
+	return l.ModuleLevel(l.module)
+}
+
+// registerLogger records the module name of a logger created with Named.
+func (l *LogzCoreImpl) registerLogger(module string) {
+	if module == "" {
+		return
+	}
+	r := l.root()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	if r.loggers == nil {
+		r.loggers = make(map[string]struct{})
+	}
+	r.loggers[module] = struct{}{}
+}
+
+// levelFor resolves the level of a module by longest-prefix matching on its dotted
+// components; at each component an unexpired override wins over the configured level.
+// The caller must hold the read lock of the root logger.
+func (l *LogzCoreImpl) levelFor(module string) LogLevel {
+	var now time.Time
+	if len(l.levelOverrides) > 0 {
+		now = time.Now()
+	}
+	for name := module; name != ""; {
+		if level, ok := l.overrideFor(name, now); ok {
+			return level
+		}
+		if level, ok := l.moduleLevels[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	if level, ok := l.overrideFor("", now); ok {
+		return level
+	}
+	if l.VLevel == "" {
+		return INFO
+	}
+	return l.VLevel
+}
+
+// overrideFor returns the unexpired override of module. The caller must hold the read lock of the root logger.
+func (l *LogzCoreImpl) overrideFor(module string, now time.Time) (LogLevel, bool) {
+	o, ok := l.levelOverrides[module]
+	if !ok || now.After(o.ExpiresAt) {
+		return "", false
+	}
+	return o.Level, true
+}
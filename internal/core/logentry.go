@@ -18,6 +18,7 @@ const (
 	SUCCESS LogLevel = "SUCCESS"
 	WARN    LogLevel = "WARN"
 	ERROR   LogLevel = "ERROR"
+	PANIC   LogLevel = "PANIC"
 	FATAL   LogLevel = "FATAL"
 	SILENT  LogLevel = "SILENT"
 )
@@ -40,6 +41,12 @@ type LogzEntry interface {
 	WithSeverity(severity int) LogzEntry
 	// WithTraceID sets the trace ID for the LogEntry.
 	WithTraceID(traceID string) LogzEntry
+	// WithTimestamp sets the timestamp for the LogEntry.
+	WithTimestamp(timestamp time.Time) LogzEntry
+	// WithCaller sets the caller for the LogEntry.
+	WithCaller(caller string) LogzEntry
+	// WithError records err, its chain and concrete types in the LogEntry.
+	WithError(err error) LogzEntry
 	// AddTag adds a tag to the LogEntry.
 	AddTag(key, value string) LogzEntry
 	// AddMetadata adds VMetadata to the LogEntry.
@@ -56,6 +63,10 @@ type LogzEntry interface {
 	GetLevel() LogLevel
 	// GetSource returns the source of the LogEntry.
 	GetSource() string
+	// GetError returns the error recorded in the LogEntry, or nil.
+	GetError() *ErrorInfo
+	// Clone returns a copy of the LogEntry that shares no tags or metadata with it.
+	Clone() LogzEntry
 	// Validate checks if the LogEntry has all required fields set.
 	Validate() error
 	// String returns a string representation of the LogEntry.
@@ -76,6 +87,7 @@ type LogEntry struct {
 	Severity  int                    `json:"severity"`            // The severity VLevel as an integer.
 	TraceID   string                 `json:"trace_id,omitempty"`  // Optional trace ID for tracing logs.
 	Caller    string                 `json:"caller,omitempty"`    // The caller of the log entry.
+	Error     *ErrorInfo             `json:"error,omitempty"`     // The error logged with the entry, with its chain.
 }
 
 // NewLogEntry creates a new instance of LogEntry with the current timestamp and initialized maps.
@@ -137,6 +149,24 @@ func (le *LogEntry) WithTraceID(traceID string) LogzEntry {
 	return le
 }
 
+// WithTimestamp sets the timestamp for the LogEntry.
+func (le *LogEntry) WithTimestamp(timestamp time.Time) LogzEntry {
+	le.Timestamp = timestamp
+	return le
+}
+
+// WithCaller sets the caller for the LogEntry.
+func (le *LogEntry) WithCaller(caller string) LogzEntry {
+	le.Caller = caller
+	return le
+}
+
+// WithError records err, its chain and concrete types in the LogEntry. A nil err clears it.
+func (le *LogEntry) WithError(err error) LogzEntry {
+	le.Error = NewErrorInfo(err)
+	return le
+}
+
 // AddTag adds a tag to the LogEntry.
 func (le *LogEntry) AddTag(key, value string) LogzEntry {
 	if le.Tags == nil {
@@ -173,6 +203,24 @@ func (le *LogEntry) GetMessage() string { return le.Message }
 // GetSource returns the source of the LogEntry.
 func (le *LogEntry) GetSource() string { return le.Source }
 
+// GetError returns the error recorded in the LogEntry, or nil.
+func (le *LogEntry) GetError() *ErrorInfo { return le.Error }
+
+// Clone returns a copy of the LogEntry that shares no tags or metadata with it.
+// Nested metadata values are shared.
+func (le *LogEntry) Clone() LogzEntry {
+	c := *le
+	c.Tags = make(map[string]string, len(le.Tags))
+	for k, v := range le.Tags {
+		c.Tags[k] = v
+	}
+	c.Metadata = make(map[string]interface{}, len(le.Metadata))
+	for k, v := range le.Metadata {
+		c.Metadata[k] = v
+	}
+	return &c
+}
+
 // Validate checks if the LogEntry has all required fields set.
 func (le *LogEntry) Validate() error {
 	if le.Timestamp.IsZero() {
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// logfmtReserved lists the keys used by the entry fields in a logfmt line.
+var logfmtReserved = map[string]bool{
+	"ts": true, "level": true, "msg": true, "source": true, "context": true,
+	"trace_id": true, "caller": true, "hostname": true, "pid": true,
+}
+
+// LogfmtFormatter formats each log entry as a logfmt line:
+//
+//	ts=2026-10-16T12:00:00Z level=info msg="disk full" source=api user.id=7
+//
+// The entry fields come first (ts, level, msg, then source, context, trace_id,
+// caller, hostname and pid when they are set), followed by the tags as "tag.<key>"
+// and the metadata, both sorted by key. Nested metadata maps are flattened into
+// dotted keys, slices are written as JSON. Metadata keys that clash with an entry
+// field, are "tag" or "meta" or start with "tag." or "meta." are prefixed with "meta.". Values are quoted only when they are empty or
+// contain spaces, '=', '"' or control characters.
+type LogfmtFormatter struct{}
+
+// Format converts the log entry to a logfmt line.
+func (f *LogfmtFormatter) Format(entry LogzEntry) (string, error) {
+	le := entryFields(entry)
+	var b strings.Builder
+
+	writeLogfmtPair(&b, "ts", le.Timestamp.Format(time.RFC3339Nano))
+	writeLogfmtPair(&b, "level", strings.ToLower(string(le.Level)))
+	writeLogfmtPair(&b, "msg", le.Message)
+	for _, kv := range []struct{ key, value string }{
+		{"source", le.Source},
+		{"context", le.Context},
+		{"trace_id", le.TraceID},
+		{"caller", le.Caller},
+		{"hostname", le.Hostname},
+	} {
+		if kv.value != "" {
+			writeLogfmtPair(&b, kv.key, kv.value)
+		}
+	}
+	if le.ProcessID != 0 {
+		writeLogfmtPair(&b, "pid", strconv.Itoa(le.ProcessID))
+	}
+	for _, k := range sortedKeys(le.Tags) {
+		writeLogfmtPair(&b, "tag."+logfmtKey(k), le.Tags[k])
+	}
+	for _, k := range sortedKeys(le.Metadata) {
+		key := logfmtKey(k)
+		// Nested maps are flattened under the key, so "tag" and "meta" are escaped as well.
+		if logfmtReserved[key] || key == "tag" || key == "meta" || strings.HasPrefix(key, "tag.") || strings.HasPrefix(key, "meta.") {
+			key = "meta." + key
+		}
+		writeLogfmtValue(&b, key, normalizeValue(le.Metadata[k]))
+	}
+	return b.String(), nil
+}
+
+// writeLogfmtValue writes a metadata value, flattening nested maps into dotted keys.
+func writeLogfmtValue(b *strings.Builder, key string, v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(val) {
+			writeLogfmtValue(b, key+"."+logfmtKey(k), normalizeValue(val[k]))
+		}
+	case []interface{}:
+		data, err := json.Marshal(val)
+		if err != nil {
+			writeLogfmtPair(b, key, fmt.Sprintf("%v", val))
+			return
+		}
+		writeLogfmtPair(b, key, string(data))
+	case nil:
+		writeLogfmtPair(b, key, "")
+	default:
+		writeLogfmtPair(b, key, fmt.Sprintf("%v", val))
+	}
+}
+
+// writeLogfmtPair writes a key=value pair, separated from the previous one by a space.
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if logfmtNeedsQuote(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// logfmtKey replaces the characters that cannot appear in a logfmt key.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtNeedsQuote reports whether a value must be quoted to be read back unchanged.
+func logfmtNeedsQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r == '=' || r == '"' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogfmtParser reads the lines written by LogfmtFormatter back into log entries.
+//
+// Entry fields are restored from their keys, "tag.<key>" pairs become tags and
+// every other pair becomes metadata, with the "meta." prefix removed. Dotted keys
+// are not nested again. Unquoted values holding a boolean or a number are
+// converted to bool, int64 or float64; everything else is kept as a string.
+type LogfmtParser struct{}
+
+// Parse converts a logfmt line into a log entry.
+func (p *LogfmtParser) Parse(line string) (LogzEntry, error) {
+	pairs, err := splitLogfmt(line)
+	if err != nil {
+		return nil, err
+	}
+	le := &LogEntry{
+		Tags:     make(map[string]string),
+		Metadata: make(map[string]interface{}),
+	}
+	for _, kv := range pairs {
+		switch kv.key {
+		case "ts":
+			ts, err := time.Parse(time.RFC3339Nano, kv.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp %q: %w", kv.value, err)
+			}
+			le.Timestamp = ts
+		case "level":
+			le.Level = LogLevel(strings.ToUpper(kv.value))
+			le.Severity = logLevels[le.Level]
+		case "msg":
+			le.Message = kv.value
+		case "source":
+			le.Source = kv.value
+		case "context":
+			le.Context = kv.value
+		case "trace_id":
+			le.TraceID = kv.value
+		case "caller":
+			le.Caller = kv.value
+		case "hostname":
+			le.Hostname = kv.value
+		case "pid":
+			pid, err := strconv.Atoi(kv.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pid %q: %w", kv.value, err)
+			}
+			le.ProcessID = pid
+		default:
+			if tag, ok := strings.CutPrefix(kv.key, "tag."); ok {
+				le.Tags[tag] = kv.value
+				continue
+			}
+			key := strings.TrimPrefix(kv.key, "meta.")
+			if kv.quoted {
+				le.Metadata[key] = kv.value
+			} else {
+				le.Metadata[key] = logfmtScalar(kv.value)
+			}
+		}
+	}
+	return le, nil
+}
+
+// logfmtPair is a key=value pair read from a logfmt line.
+type logfmtPair struct {
+	key, value string
+	quoted     bool
+}
+
+// splitLogfmt splits a logfmt line into its pairs. A key without '=' is read as "key=true".
+func splitLogfmt(line string) ([]logfmtPair, error) {
+	var pairs []logfmtPair
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i >= len(line) {
+			break
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if i >= len(line) || line[i] == ' ' {
+			pairs = append(pairs, logfmtPair{key: key, value: "true"})
+			continue
+		}
+		i++ // skip '='
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, fmt.Errorf("unterminated quoted value for key %q", key)
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value for key %q: %w", key, err)
+			}
+			pairs = append(pairs, logfmtPair{key: key, value: value, quoted: true})
+			i = end + 1
+			continue
+		}
+		start = i
+		for i < len(line) && line[i] != ' ' {
+			i++
+		}
+		pairs = append(pairs, logfmtPair{key: key, value: line[start:i]})
+	}
+	return pairs, nil
+}
+
+// logfmtScalar converts an unquoted value into a bool or a number when it holds one.
+func logfmtScalar(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if value == "" || (value[0] != '-' && (value[0] < '0' || value[0] > '9')) {
+		return value
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
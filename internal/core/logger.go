@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"io"
 	"sync/atomic"
 
@@ -9,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
 type LogMode string
@@ -20,6 +22,8 @@ const (
 	YAML LogFormat = "yaml"
 	XML  LogFormat = "xml"
 	RAW  LogFormat = "raw"
+
+	LOGFMT LogFormat = "logfmt"
 )
 
 const (
@@ -35,8 +39,9 @@ var logLevels = map[LogLevel]int{
 	SUCCESS: 5,
 	WARN:    6,
 	ERROR:   7,
-	FATAL:   8,
-	SILENT:  9,
+	PANIC:   8,
+	FATAL:   9,
+	SILENT:  10,
 }
 
 // LogzCoreImpl represents a core with configuration and VMetadata.
@@ -59,6 +64,27 @@ type LogzCoreImpl struct {
 	VMetadata map[string]interface{}
 	VMode     LogMode // Mode control: service or standalone
 	Mu        sync.RWMutex
+
+	sampler      *Sampler            // limits repeated entries; nil logs everything
+	moduleLevels map[string]LogLevel // levels of the named loggers, by module name
+
+	stackLevel LogLevel       // lowest level capturing the stack of entries carrying an error; "" disables it
+	exitFunc   func(code int) // terminates the process on FATAL; nil uses os.Exit
+	fatalMode  FatalMode      // whether FATAL exits or panics
+	recovery   RecoveryConfig // handling of the panics recovered by RecoverAndLog and Go
+
+	notifyWg sync.WaitGroup // notifications still being sent; Flush waits for them
+
+	levelOverrides map[string]LevelOverride // temporary levels by module; "" overrides the default level
+	loggers        map[string]struct{}      // module names of the loggers created with Named
+
+	parent *LogzCoreImpl          // logger this child was derived from; nil for a root logger
+	fields map[string]interface{} // fields bound by With; never mutated after creation
+	module string                 // dotted name given to Named, without the root prefix
+	err    error                  // error bound by WithError
+
+	hooks   []MultiHook  // hooks added to this logger, in order
+	hooksMu sync.RWMutex // guards hooks
 }
 
 // NewLogger creates a new instance of LogzCoreImpl with the provided configuration.
@@ -134,116 +160,371 @@ func NewLogger(prefix string) LogzLogger { //VConfig Config) LogzLogger {
 	return lgr
 }
 
+// With returns a child logger carrying the given fields in addition to the ones bound to l.
+// The child shares writer, level, configuration and global VMetadata with its root logger,
+// and the fields of l are never modified.
+func (l *LogzCoreImpl) With(fields map[string]interface{}) LogzLogger {
+	bound := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		bound[k] = v
+	}
+	for k, v := range fields {
+		bound[k] = v
+	}
+	return l.child(l.Name(), l.module, bound)
+}
+
+// Named returns a child logger whose name is the dotted concatenation of the name of l and name.
+// The logger name is emitted as the Source of every entry.
+func (l *LogzCoreImpl) Named(name string) LogzLogger {
+	fullName, module := l.Name(), l.module
+	if name != "" {
+		if fullName != "" {
+			fullName += "."
+		}
+		fullName += name
+		if module != "" {
+			module += "."
+		}
+		module += name
+	}
+	l.registerLogger(module)
+	return l.child(fullName, module, l.fields)
+}
+
+// Name returns the dotted name of the logger, starting with the prefix of its root logger.
+func (l *LogzCoreImpl) Name() string {
+	if name := l.prefix.Load(); name != nil {
+		return *name
+	}
+	return ""
+}
+
+// child creates a logger derived from l with the given name, module and bound fields.
+func (l *LogzCoreImpl) child(name, module string, fields map[string]interface{}) *LogzCoreImpl {
+	c := &LogzCoreImpl{
+		parent: l,
+		fields: fields,
+		module: module,
+		err:    l.err,
+	}
+	c.prefix.Store(&name)
+	return c
+}
+
+// root returns the logger holding the shared state of l.
+func (l *LogzCoreImpl) root() *LogzCoreImpl {
+	r := l
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
 // SetMetadata sets a VMetadata key-value pair for the LogzCoreImpl.
+// Child loggers set the key on their root logger; use With for request-scoped fields.
 func (l *LogzCoreImpl) SetMetadata(key string, value interface{}) {
+	if l.parent != nil {
+		l.root().SetMetadata(key, value)
+		return
+	}
 	l.Mu.Lock()
 	defer l.Mu.Unlock()
 	l.VMetadata[key] = value
 }
 
-// shouldLog checks if the log VLevel should be logged.
+// shouldLog checks if the log VLevel should be logged, taking the module levels into account.
 func (l *LogzCoreImpl) shouldLog(level LogLevel) bool {
-	return logLevels[level] >= logLevels[l.VLevel]
+	return logLevels[level] >= logLevels[l.EffectiveLevel()]
 }
 
 // log logs a message with the specified VLevel and context.
-func (l *LogzCoreImpl) log(level LogLevel, msg string, ctx map[string]interface{}) {
-	if !l.shouldLog(level) {
+// The goCtx carries request-scoped values such as the trace ID; it may be context.Background().
+// Disabled PANIC and FATAL entries are not written, but they still panic or exit.
+func (l *LogzCoreImpl) log(goCtx context.Context, level LogLevel, msg string, ctx map[string]interface{}) {
+	if !l.Enabled(level) {
+		if level == PANIC || level == FATAL {
+			l.terminate(l.newEntry(goCtx, level, msg, ctx))
+		}
 		return
 	}
+	l.process(l.newEntry(goCtx, level, msg, ctx))
+}
 
-	l.Mu.RLock()
-	defer l.Mu.RUnlock()
-
-	entry := NewLogEntry().
-		WithLevel(level).
-		WithMessage(msg).
-		WithSeverity(logLevels[level])
+// newEntry builds the entry of a log call from the message, the fields bound to l,
+// the global VMetadata and the error bound by WithError.
+func (l *LogzCoreImpl) newEntry(goCtx context.Context, level LogLevel, msg string, ctx map[string]interface{}) LogzEntry {
+	entry := &LogEntry{
+		Timestamp: time.Now(),
+		Level:     level,
+		Source:    l.Name(),
+		Message:   msg,
+		Tags:      make(map[string]string),
+		Metadata:  make(map[string]interface{}, len(ctx)),
+		Severity:  logLevels[level],
+		TraceID:   TraceIDFromContext(goCtx),
+		Caller:    callerInfo(),
+		Error:     l.errorInfo(level),
+	}
 
-	// Merge global and local VMetadata
-	finalContext := mergeContext(l.VMetadata, ctx)
-	for k, v := range finalContext {
-		entry.AddMetadata(k, v)
+	// Fields bound to the logger take precedence over the global VMetadata,
+	// and the fields of the call over both
+	r := l.root()
+	r.Mu.RLock()
+	for k, v := range r.VMetadata {
+		entry.Metadata[k] = v
 	}
+	r.Mu.RUnlock()
+	for k, v := range l.fields {
+		entry.Metadata[k] = v
+	}
+	for k, v := range ctx {
+		entry.Metadata[k] = v
+	}
+	return entry
+}
+
+// Enabled reports whether entries of the given level are logged.
+func (l *LogzCoreImpl) Enabled(level LogLevel) bool {
+	return l.shouldLog(level)
+}
 
-	// Merge global and local VMetadata
-	finalMetadata := mergeMetadata(l.VMetadata, ctx)
-	for k, v := range finalMetadata {
-		entry.AddMetadata(k, v)
+// Emit routes a prebuilt entry through the hooks, writers, notifiers and metrics of the logger.
+// Missing source, severity and metadata keys are completed from the logger name and fields.
+// Entries below the logger level are discarded; PANIC and FATAL entries still panic or exit.
+func (l *LogzCoreImpl) Emit(entry LogzEntry) {
+	if entry == nil {
+		return
+	}
+	if level := entry.GetLevel(); !l.Enabled(level) {
+		if level == PANIC || level == FATAL {
+			l.terminate(entry)
+		}
+		return
+	}
+	if entry.GetSource() == "" {
+		entry.WithSource(l.Name())
+	}
+	if le, ok := entry.(*LogEntry); ok && le.Severity == 0 {
+		le.Severity = logLevels[le.Level]
+	}
+	metadata := entry.GetMetadata()
+	for k, v := range l.baseFields() {
+		if _, exists := metadata[k]; !exists {
+			entry.AddMetadata(k, v)
+		}
 	}
+	l.process(entry)
+}
+
+// baseFields returns the global VMetadata of the root logger merged with the fields bound to l.
+func (l *LogzCoreImpl) baseFields() map[string]interface{} {
+	r := l.root()
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	return mergeContext(r.VMetadata, l.fields)
+}
 
-	if level != SILENT {
+// dispatch writes the entry, notifies the configured notifiers in service mode and updates the metrics.
+// It must be called on a root logger, after the hooks ran (see process).
+func (l *LogzCoreImpl) dispatch(entry LogzEntry) {
+	level := entry.GetLevel()
+
+	// Take a snapshot of the shared state so slow writers do not hold the lock
+	l.Mu.RLock()
+	writer, config, mode := l.VWriter, l.VConfig, l.VMode
+	l.Mu.RUnlock()
+
+	if level != SILENT && writer != nil {
 		// Write the log using the configured VWriter
-		if err := l.VWriter.Write(entry); err != nil {
+		if err := writer.Write(entry); err != nil {
 			log.Printf("ErrorCtx writing log: %v", err)
 		}
 	}
 
-	// Only in service VMode, notify via Notifiers
-	if l.VMode == ModeService && l.VConfig != nil {
-		//for _, name := range l.VConfig.NotifierManager().ListNotifiers() {
-		//	if notifier, ok := l.VConfig.NotifierManager().GetNotifier(name); ok {
-		//		if notifier != nil {
-		//			ntf := notifier
-		//			if ntfErr := ntf.Notify(entry); ntfErr != nil {
-		//				log.Printf("ErrorCtx notifying %s: %v", name, ntfErr)
-		//			}
-		//		}
-		//	}
-		//}
+	// Only in service VMode, notify via Notifiers. Notifications are sent in the background,
+	// each with its own copy of the entry, so that a slow notifier does not hold the caller;
+	// Flush waits for them.
+	if mode == ModeService && config != nil {
+		if nm, ok := config.NotifierManager().(NotifierManager); ok && nm != nil {
+			for _, name := range nm.ListNotifiers() {
+				if notifier, ok := nm.GetNotifier(name); ok && notifier != nil {
+					l.notifyWg.Add(1)
+					go func(name string, ntf Notifier, e LogzEntry) {
+						defer l.notifyWg.Done()
+						if ntfErr := ntf.Notify(e); ntfErr != nil {
+							log.Printf("ErrorCtx notifying %s: %v", name, ntfErr)
+						}
+					}(name, notifier, entry.Clone())
+				}
+			}
+		}
 	}
 
 	// Update metrics in PrometheusManager, if enabled
-	if l.VMode == ModeService {
+	if mode == ModeService {
 		pm := GetPrometheusManager()
 		if pm.IsEnabled() {
 			pm.IncrementMetric("logs_total", 1)
 			pm.IncrementMetric("logs_total_"+string(level), 1)
 		}
 	}
-
-	// Terminate the process in case of FATAL log
-	if level == FATAL {
-		os.Exit(1)
-	}
 }
 
 // TraceCtx logs a trace message with context.
-func (l *LogzCoreImpl) TraceCtx(msg string, ctx map[string]interface{}) { l.log(TRACE, msg, ctx) }
+func (l *LogzCoreImpl) TraceCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), TRACE, msg, ctx)
+}
 
 // NoticeCtx logs a notice message with context.
-func (l *LogzCoreImpl) NoticeCtx(msg string, ctx map[string]interface{}) { l.log(NOTICE, msg, ctx) }
+func (l *LogzCoreImpl) NoticeCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), NOTICE, msg, ctx)
+}
 
 // SuccessCtx logs a success message with context.
-func (l *LogzCoreImpl) SuccessCtx(msg string, ctx map[string]interface{}) { l.log(SUCCESS, msg, ctx) }
+func (l *LogzCoreImpl) SuccessCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), SUCCESS, msg, ctx)
+}
 
 // DebugCtx logs a debug message with context.
-func (l *LogzCoreImpl) DebugCtx(msg string, ctx map[string]interface{}) { l.log(DEBUG, msg, ctx) }
+func (l *LogzCoreImpl) DebugCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), DEBUG, msg, ctx)
+}
 
 // InfoCtx logs an info message with context.
-func (l *LogzCoreImpl) InfoCtx(msg string, ctx map[string]interface{}) { l.log(INFO, msg, ctx) }
+func (l *LogzCoreImpl) InfoCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), INFO, msg, ctx)
+}
 
 // WarnCtx logs a warning message with context.
-func (l *LogzCoreImpl) WarnCtx(msg string, ctx map[string]interface{}) { l.log(WARN, msg, ctx) }
+func (l *LogzCoreImpl) WarnCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), WARN, msg, ctx)
+}
 
 // ErrorCtx logs an error message with context.
-func (l *LogzCoreImpl) ErrorCtx(msg string, ctx map[string]interface{}) { l.log(ERROR, msg, ctx) }
+func (l *LogzCoreImpl) ErrorCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), ERROR, msg, ctx)
+}
 
 // FatalCtx logs a fatal message with context and terminates the process.
-func (l *LogzCoreImpl) FatalCtx(msg string, ctx map[string]interface{}) { l.log(FATAL, msg, ctx) }
+func (l *LogzCoreImpl) FatalCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), FATAL, msg, ctx)
+}
+
+// PanicCtx logs a panic message with context, flushes the writers and panics with a *PanicError.
+func (l *LogzCoreImpl) PanicCtx(msg string, ctx map[string]interface{}) {
+	l.log(context.Background(), PANIC, msg, ctx)
+}
+
+// TraceContext logs a trace message, reading request-scoped values from ctx.
+func (l *LogzCoreImpl) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, TRACE, msg, fields)
+}
+
+// NoticeContext logs a notice message, reading request-scoped values from ctx.
+func (l *LogzCoreImpl) NoticeContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, NOTICE, msg, fields)
+}
+
+// SuccessContext logs a success message, reading request-scoped values from ctx.
+func (l *LogzCoreImpl) SuccessContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, SUCCESS, msg, fields)
+}
+
+// DebugContext logs a debug message, reading request-scoped values from ctx.
+func (l *LogzCoreImpl) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, DEBUG, msg, fields)
+}
+
+// InfoContext logs an info message, reading request-scoped values from ctx.
+func (l *LogzCoreImpl) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, INFO, msg, fields)
+}
+
+// WarnContext logs a warning message, reading request-scoped values from ctx.
+func (l *LogzCoreImpl) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, WARN, msg, fields)
+}
+
+// ErrorContext logs an error message, reading request-scoped values from ctx.
+func (l *LogzCoreImpl) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, ERROR, msg, fields)
+}
+
+// FatalContext logs a fatal message, reading request-scoped values from ctx, and terminates the process.
+func (l *LogzCoreImpl) FatalContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, FATAL, msg, fields)
+}
+
+// PanicContext logs a panic message, reading request-scoped values from ctx, and panics with a *PanicError.
+func (l *LogzCoreImpl) PanicContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, PANIC, msg, fields)
+}
+
+// logContext merges the request-scoped values of ctx with the given fields and logs the message.
+// When ctx carries a scoped logger other than l, the entry is routed through that logger instead.
+func (l *LogzCoreImpl) logContext(ctx context.Context, level LogLevel, msg string, fields []map[string]interface{}) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if scoped := LoggerFromContext(ctx); scoped != nil && scoped != LogzLogger(l) {
+		// Clear the scoped logger so the delegate does not bounce the entry back.
+		logContextAt(scoped, context.WithValue(ctx, loggerKey, nil), level, msg, fields)
+		return
+	}
+
+	merged := contextFields(ctx)
+	for _, f := range fields {
+		for k, v := range f {
+			merged[k] = v
+		}
+	}
+	l.log(ctx, level, msg, merged)
+}
+
+// logContextAt calls the *Context method of lgr matching the given level.
+func logContextAt(lgr LogzCore, ctx context.Context, level LogLevel, msg string, fields []map[string]interface{}) {
+	switch level {
+	case TRACE:
+		lgr.TraceContext(ctx, msg, fields...)
+	case NOTICE:
+		lgr.NoticeContext(ctx, msg, fields...)
+	case SUCCESS:
+		lgr.SuccessContext(ctx, msg, fields...)
+	case DEBUG:
+		lgr.DebugContext(ctx, msg, fields...)
+	case WARN:
+		lgr.WarnContext(ctx, msg, fields...)
+	case ERROR:
+		lgr.ErrorContext(ctx, msg, fields...)
+	case PANIC:
+		lgr.PanicContext(ctx, msg, fields...)
+	case FATAL:
+		lgr.FatalContext(ctx, msg, fields...)
+	default:
+		lgr.InfoContext(ctx, msg, fields...)
+	}
+}
 
 func (l *LogzCoreImpl) SetLevel(level interface{}) {
+	if l.parent != nil {
+		l.root().SetLevel(level)
+		return
+	}
 	l.Mu.Lock()
 	defer l.Mu.Unlock()
 	if lvl, ok := level.(LogLevel); ok {
 		l.VLevel = lvl
 	} else if lvlStr, ok := level.(string); ok {
-		l.VLevel = LogLevel(lvlStr)
+		l.VLevel = LogLevel(strings.ToUpper(lvlStr))
 	} else {
 		log.Println("Invalid log level type")
 	}
 }
 func (l *LogzCoreImpl) GetLevel() interface{} {
+	if l.parent != nil {
+		return l.root().GetLevel()
+	}
 	l.Mu.RLock()
 	defer l.Mu.RUnlock()
 
@@ -254,6 +535,10 @@ func (l *LogzCoreImpl) GetLevel() interface{} {
 }
 
 func (l *LogzCoreImpl) SetWriter(writer any) {
+	if l.parent != nil {
+		l.root().SetWriter(writer)
+		return
+	}
 	l.Mu.Lock()
 	defer l.Mu.Unlock()
 	if osFile, ok := writer.(*os.File); ok {
@@ -265,6 +550,9 @@ func (l *LogzCoreImpl) SetWriter(writer any) {
 	}
 }
 func (l *LogzCoreImpl) GetWriter() interface{} {
+	if l.parent != nil {
+		return l.root().GetWriter()
+	}
 	l.Mu.RLock()
 	defer l.Mu.RUnlock()
 	if l.VWriter == nil {
@@ -274,6 +562,9 @@ func (l *LogzCoreImpl) GetWriter() interface{} {
 }
 
 func (l *LogzCoreImpl) GetMode() interface{} {
+	if l.parent != nil {
+		return l.root().GetMode()
+	}
 	l.Mu.RLock()
 	defer l.Mu.RUnlock()
 	if l.VMode == "" {
@@ -283,15 +574,61 @@ func (l *LogzCoreImpl) GetMode() interface{} {
 }
 
 func (l *LogzCoreImpl) SetConfig(config interface{}) {
+	if l.parent != nil {
+		l.root().SetConfig(config)
+		return
+	}
 	l.Mu.Lock()
 	defer l.Mu.Unlock()
 	if cfg, ok := config.(Config); ok {
 		l.VConfig = cfg
+		for module, level := range cfg.ModuleLevels() {
+			if l.moduleLevels == nil {
+				l.moduleLevels = make(map[string]LogLevel)
+			}
+			l.moduleLevels[module] = level
+		}
 	} else {
 		log.Println("Invalid config type")
 	}
 }
+
+// SetFormat sets the format of the entries written by the default writer.
+// It accepts a LogFormat or a string naming one of json, text, yaml, xml, raw or logfmt.
+func (l *LogzCoreImpl) SetFormat(format interface{}) {
+	if l.parent != nil {
+		l.root().SetFormat(format)
+		return
+	}
+	var f LogFormat
+	if lf, ok := format.(LogFormat); ok {
+		f = lf
+	} else if str, ok := format.(string); ok {
+		f = LogFormat(str)
+	} else {
+		log.Println("Invalid log format type")
+		return
+	}
+	formatter := NewFormatter(f)
+	if formatter == nil {
+		log.Printf("Invalid log format: %s", f)
+		return
+	}
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+	if l.VWriter == nil {
+		l.VWriter = NewDefaultWriter[any](os.Stdout, formatter)
+	} else if dw, ok := l.VWriter.(*DefaultWriter[any]); ok {
+		dw.SetFormatter(formatter)
+	}
+	if l.VConfig != nil {
+		l.VConfig.SetFormat(strings.ToLower(string(f)))
+	}
+}
 func (l *LogzCoreImpl) GetConfig() interface{} {
+	if l.parent != nil {
+		return l.root().GetConfig()
+	}
 	l.Mu.RLock()
 	defer l.Mu.RUnlock()
 	if l.VConfig == nil {
@@ -323,15 +660,3 @@ func mergeContext(global, local map[string]interface{}) map[string]interface{} {
 	}
 	return merged
 }
-
-// mergeMetadata merges global and local context maps.
-func mergeMetadata(global, local map[string]interface{}) map[string]interface{} {
-	merged := make(map[string]interface{})
-	for k, v := range global {
-		merged[k] = v
-	}
-	for k, v := range local {
-		merged[k] = v
-	}
-	return merged
-}
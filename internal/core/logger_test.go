@@ -1,5 +1,24 @@
 package core
 
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
 /*func TestNewLogger(t *testing.T) {
 	configManager := NewConfigManager()
 	if configManager == nil {
@@ -159,3 +178,706 @@ func TestDynamicConfigChanges(t *testing.T) {
 	}
 }
 */
+
+// newBufferLogger returns a logger writing JSON entries into buf.
+func newBufferLogger(buf *bytes.Buffer) *LogzCoreImpl {
+	lgr := NewLogger("test").(*LogzCoreImpl)
+	lgr.SetWriter(NewDefaultWriter[any](buf, &JSONFormatter{}))
+	return lgr
+}
+
+// decodeEntries decodes the JSON lines written into buf.
+func decodeEntries(t *testing.T, buf *bytes.Buffer) []LogEntry {
+	t.Helper()
+	var entries []LogEntry
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var e LogEntry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("decoding entry: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestContextMethods(t *testing.T) {
+	var buf bytes.Buffer
+	lgr := newBufferLogger(&buf)
+
+	ctx := ContextWithTraceID(context.Background(), "trace-1")
+	ctx = ContextWithRequestID(ctx, "req-1")
+	ctx = ContextWithSpanID(ctx, "span-1")
+	lgr.InfoContext(ctx, "handled", map[string]interface{}{"status": 200})
+
+	entries := decodeEntries(t, &buf)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.TraceID != "trace-1" {
+		t.Errorf("expected trace ID 'trace-1', got '%s'", e.TraceID)
+	}
+	if e.Metadata["request_id"] != "req-1" || e.Metadata["span_id"] != "span-1" {
+		t.Errorf("expected request and span IDs in metadata, got %v", e.Metadata)
+	}
+	if e.Metadata["status"] != float64(200) {
+		t.Errorf("expected status field, got %v", e.Metadata["status"])
+	}
+}
+
+func TestContextScopedLogger(t *testing.T) {
+	var rootBuf, scopedBuf bytes.Buffer
+	root := newBufferLogger(&rootBuf)
+	scoped := newBufferLogger(&scopedBuf)
+
+	ctx := ContextWithLogger(context.Background(), scoped)
+	root.WarnContext(ctx, "routed")
+
+	if rootBuf.Len() != 0 {
+		t.Errorf("expected root logger to stay silent, got '%s'", rootBuf.String())
+	}
+	if entries := decodeEntries(t, &scopedBuf); len(entries) != 1 || entries[0].Message != "routed" {
+		t.Errorf("expected scoped logger to receive the entry, got %v", entries)
+	}
+}
+
+func TestChildLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	root := newBufferLogger(&buf)
+
+	reqLogger := root.With(map[string]interface{}{"request": "a"})
+	dbLogger := reqLogger.Named("db").Named("pool")
+	dbLogger.InfoCtx("query", map[string]interface{}{"rows": 3})
+	root.InfoCtx("plain", nil)
+
+	entries := decodeEntries(t, &buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Source != "test.db.pool" {
+		t.Errorf("expected source 'test.db.pool', got '%s'", entries[0].Source)
+	}
+	if entries[0].Metadata["request"] != "a" || entries[0].Metadata["rows"] != float64(3) {
+		t.Errorf("expected bound and local fields, got %v", entries[0].Metadata)
+	}
+	if _, leaked := entries[1].Metadata["request"]; leaked {
+		t.Errorf("expected bound fields to stay on the child, got %v", entries[1].Metadata)
+	}
+
+	// Children share the level of their root logger.
+	root.SetLevel(ERROR)
+	dbLogger.InfoCtx("filtered", nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected child to honor the root level, got '%s'", buf.String())
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	lgr := newBufferLogger(&buf)
+	lgr.SetLevel(DEBUG)
+
+	sl := NewSlogLogger(lgr.Named("slog")).With("service", "api").WithGroup("req")
+	sl.Log(context.Background(), SlogLevelNotice, "served", "path", "/x", slog.Group("user", "id", 7))
+	sl.Debug("details")
+
+	entries := decodeEntries(t, &buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Level != NOTICE || e.Source != "test.slog" {
+		t.Errorf("expected NOTICE from 'test.slog', got %s from '%s'", e.Level, e.Source)
+	}
+	req, _ := e.Metadata["req"].(map[string]interface{})
+	user, _ := req["user"].(map[string]interface{})
+	if e.Metadata["service"] != "api" || req["path"] != "/x" || user["id"] != float64(7) {
+		t.Errorf("unexpected metadata layout: %v", e.Metadata)
+	}
+	if _, ok := entries[1].Metadata["req"]; ok {
+		t.Errorf("expected empty group to be omitted, got %v", entries[1].Metadata)
+	}
+
+	// Records above slog.LevelError are logged as ERROR and never terminate the process.
+	lgr.SetExitFunc(func(int) { t.Error("a slog record must not exit") })
+	for _, level := range []slog.Level{slog.LevelError, 10, 12, 100} {
+		sl.Log(context.Background(), level, "library failure")
+	}
+	for _, e := range decodeEntries(t, &buf) {
+		if e.Level != ERROR {
+			t.Errorf("expected ERROR, got %s", e.Level)
+		}
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var buf bytes.Buffer
+	root := newBufferLogger(&buf)
+
+	root.AddHook(func(e LogzEntry) (LogzEntry, bool) {
+		e.AddMetadata("env", "test")
+		return e, true
+	})
+	child := root.Named("audit")
+	child.AddHook(func(e LogzEntry) (LogzEntry, bool) {
+		return e, e.GetMessage() != "drop me"
+	})
+	child.AddMultiHook(func(e LogzEntry) []LogzEntry {
+		copied := e.Clone().WithSource("audit.copy")
+		return []LogzEntry{e, copied}
+	})
+
+	child.InfoCtx("kept", nil)
+	child.InfoCtx("drop me", nil)
+	root.InfoCtx("root only", nil)
+
+	entries := decodeEntries(t, &buf)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	want := []struct{ source, msg string }{
+		{"test.audit", "kept"},
+		{"audit.copy", "kept"},
+		{"test", "root only"},
+	}
+	for i, w := range want {
+		if entries[i].Source != w.source || entries[i].Message != w.msg {
+			t.Errorf("entry %d: expected %s/%s, got %s/%s", i, w.source, w.msg, entries[i].Source, entries[i].Message)
+		}
+		if entries[i].Metadata["env"] != "test" {
+			t.Errorf("entry %d: expected the root hook to run, got %v", i, entries[i].Metadata)
+		}
+	}
+}
+
+func TestSampler(t *testing.T) {
+	var buf bytes.Buffer
+	lgr := newBufferLogger(&buf)
+	lgr.SetLevel(DEBUG)
+	sampler := NewSampler(SamplingConfig{
+		Tick:   time.Hour,
+		Rule:   SamplingRule{First: 2, Thereafter: 3},
+		Levels: map[LogLevel]SamplingRule{ERROR: {}},
+	})
+	lgr.Named("hot").SetSampler(sampler)
+
+	for i := 0; i < 10; i++ {
+		lgr.DebugCtx("cache miss", nil)
+		lgr.ErrorCtx("failed", nil)
+	}
+	lgr.DebugCtx("other message", nil)
+
+	counts := make(map[string]int)
+	for _, e := range decodeEntries(t, &buf) {
+		counts[e.Message]++
+	}
+	// Entries 1, 2, 5 and 8 of "cache miss" are kept.
+	if counts["cache miss"] != 4 || counts["failed"] != 10 || counts["other message"] != 1 {
+		t.Errorf("unexpected sampled counts: %v", counts)
+	}
+	if sampler.Dropped() != 6 {
+		t.Errorf("expected 6 sampled out entries, got %d", sampler.Dropped())
+	}
+
+	// The drops of the current window are reported on Flush, without waiting for the next one.
+	if err := lgr.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sampler.mu.Lock()
+	pending := len(sampler.sampled)
+	sampler.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("expected the sampled out entries to be reported on Flush, %d levels pending", pending)
+	}
+}
+
+func TestModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	root := newBufferLogger(&buf)
+	if err := root.SetLevelSpec("warn,db=debug,http.client=error"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	db := root.Named("db")
+	pool := db.Named("pool").With(map[string]interface{}{"conn": 1})
+	client := root.Named("http").Named("client")
+	server := root.Named("http").Named("server")
+	dbx := root.Named("dbx")
+
+	cases := []struct {
+		logger LogzLogger
+		want   LogLevel
+	}{
+		{root, WARN}, {db, DEBUG}, {pool, DEBUG}, {client, ERROR}, {server, WARN}, {dbx, WARN},
+	}
+	for _, c := range cases {
+		if got := c.logger.EffectiveLevel(); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.logger.(*LogzCoreImpl).Name(), c.want, got)
+		}
+	}
+
+	pool.DebugCtx("pool debug", nil)
+	client.WarnCtx("client warn", nil)
+	root.SetModuleLevel("http", INFO)
+	server.InfoCtx("server info", nil)
+	root.SetModuleLevel("db", "")
+	db.DebugCtx("db debug", nil)
+
+	var msgs []string
+	for _, e := range decodeEntries(t, &buf) {
+		msgs = append(msgs, e.Message)
+	}
+	if len(msgs) != 2 || msgs[0] != "pool debug" || msgs[1] != "server info" {
+		t.Errorf("unexpected entries: %v", msgs)
+	}
+
+	if _, _, err := ParseLevelSpec("info,db=verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestInitializeGlobalLoggerModuleLevels(t *testing.T) {
+	saved := globalLogger
+	globalLogger = nil
+	defer func() { globalLogger = saved }()
+
+	initializeGlobalLogger(&ConfigImpl{VlModuleLevels: map[string]LogLevel{"db": DEBUG, "http.client": ERROR}})
+
+	if got := globalLogger.Named("db").EffectiveLevel(); got != DEBUG {
+		t.Errorf("db: expected DEBUG, got %s", got)
+	}
+	if got := globalLogger.Named("http").Named("client").EffectiveLevel(); got != ERROR {
+		t.Errorf("http.client: expected ERROR, got %s", got)
+	}
+}
+
+func TestAdminAPI(t *testing.T) {
+	var buf bytes.Buffer
+	root := newBufferLogger(&buf)
+	root.SetLevel(INFO)
+	db := root.Named("db")
+	root.Named("http").Named("client")
+
+	mux := http.NewServeMux()
+	RegisterAdminHandlers(mux, root, AdminConfig{Token: "s3cret"})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	ctx := context.Background()
+
+	anonymous := &AdminClient{BaseURL: srv.URL}
+	if _, err := anonymous.Levels(ctx); err == nil {
+		t.Error("expected the request without token to be rejected")
+	}
+
+	client := &AdminClient{BaseURL: srv.URL, Token: "s3cret"}
+	if err := client.SetLevel(ctx, "db", DEBUG, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := db.EffectiveLevel(); got != DEBUG {
+		t.Errorf("expected db at DEBUG, got %s", got)
+	}
+	if err := client.SetLevel(ctx, "", ERROR, time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	levels, err := client.Levels(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if levels.Level != ERROR || levels.Modules["db"] != DEBUG || levels.Overrides[""].Level != ERROR {
+		t.Errorf("unexpected levels: %+v", levels)
+	}
+
+	loggers, err := client.Loggers(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, l := range loggers {
+		names = append(names, l.Name+"="+string(l.Level))
+	}
+	if want := "[db=DEBUG http=ERROR http.client=ERROR]"; fmt.Sprint(names) != want {
+		t.Errorf("expected loggers %s, got %v", want, names)
+	}
+
+	// Expire the override without waiting for its TTL.
+	root.Mu.Lock()
+	o := root.levelOverrides[""]
+	o.ExpiresAt = time.Now().Add(-time.Second)
+	root.levelOverrides[""] = o
+	root.Mu.Unlock()
+	if got := root.EffectiveLevel(); got != INFO {
+		t.Errorf("expected the override to expire, got %s", got)
+	}
+	if err := client.ResetLevel(ctx, "db"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info, err := client.Level(ctx, "db"); err != nil || info.Level != INFO {
+		t.Errorf("expected db back at INFO, got %+v (%v)", info, err)
+	}
+	if err := client.SetLevel(ctx, "db", "VERBOSE", 0); err == nil {
+		t.Error("expected an invalid level to be rejected")
+	}
+
+	// Without a token only loopback clients are served.
+	open := http.NewServeMux()
+	RegisterAdminHandlers(open, root, AdminConfig{})
+	for addr, want := range map[string]int{"127.0.0.1:5000": http.StatusOK, "[::1]:5000": http.StatusOK, "10.0.0.7:5000": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodPut, "/admin/levels", strings.NewReader(`{"level":"debug"}`))
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		open.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s: expected status %d, got %d", addr, want, rec.Code)
+		}
+	}
+}
+
+// recordNotifier is a Notifier recording the entries it is notified of.
+type recordNotifier struct {
+	Notifier
+	delay   time.Duration
+	mu      sync.Mutex
+	entries []LogzEntry
+}
+
+func (n *recordNotifier) Notify(entry LogzEntry) error {
+	time.Sleep(n.delay)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.entries = append(n.entries, entry)
+	return nil
+}
+
+func (n *recordNotifier) messages() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	msgs := make([]string, 0, len(n.entries))
+	for _, e := range n.entries {
+		msgs = append(msgs, e.GetMessage())
+	}
+	return msgs
+}
+
+// newNotifiedLogger returns a service-mode logger writing to buf and notifying n.
+func newNotifiedLogger(buf *bytes.Buffer, n Notifier) *LogzCoreImpl {
+	lgr := newBufferLogger(buf)
+	lgr.VMode = ModeService
+	lgr.SetConfig(&ConfigImpl{VlNotifierManager: NewNotifierManager(map[string]Notifier{"record": n})})
+	return lgr
+}
+
+func TestNotifiers(t *testing.T) {
+	var buf bytes.Buffer
+	n := &recordNotifier{delay: 20 * time.Millisecond}
+	lgr := newNotifiedLogger(&buf, n)
+
+	start := time.Now()
+	lgr.InfoCtx("notified", nil)
+	if elapsed := time.Since(start); elapsed >= n.delay {
+		t.Errorf("expected the notification not to hold the caller, took %v", elapsed)
+	}
+	if err := lgr.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := n.messages(); len(got) != 1 || got[0] != "notified" {
+		t.Errorf("expected the notifier to receive the entry after Flush, got %v", got)
+	}
+	if len(decodeEntries(t, &buf)) != 1 {
+		t.Error("expected the entry to be written too")
+	}
+
+	lgr.VMode = ModeStandalone
+	lgr.InfoCtx("standalone", nil)
+	lgr.Flush(context.Background())
+	if got := n.messages(); len(got) != 1 {
+		t.Errorf("expected no notification outside service mode, got %v", got)
+	}
+}
+
+func TestFatalAndPanic(t *testing.T) {
+	out := &recordWriter{delay: 5 * time.Millisecond}
+	lgr := NewLogger("test").(*LogzCoreImpl)
+	lgr.SetWriter(NewAsyncWriter(out, AsyncWriterConfig{QueueSize: 16}))
+
+	exitCode := -1
+	lgr.SetExitFunc(func(code int) { exitCode = code })
+	lgr.Named("db").InfoCtx("before", nil)
+	lgr.Named("db").FatalCtx("boom", nil)
+	if exitCode != 1 {
+		t.Errorf("expected the exit function to be called with 1, got %d", exitCode)
+	}
+	if got := out.messages(); len(got) != 2 || got[1] != "boom" {
+		t.Errorf("expected the writer to be flushed before exit, got %v", got)
+	}
+
+	recovered := func(f func()) (r interface{}) {
+		defer func() { r = recover() }()
+		f()
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	lgr = newBufferLogger(buf)
+	lgr.SetExitFunc(func(int) { t.Error("PANIC must not exit") })
+	r := recovered(func() { lgr.PanicCtx("panicked", map[string]interface{}{"k": "v"}) })
+	if pe, ok := r.(*PanicError); !ok || pe.Error() != "panicked" || pe.Entry.GetLevel() != PANIC {
+		t.Errorf("expected a *PanicError, got %#v", r)
+	}
+
+	lgr.SetFatalMode(FatalPanic)
+	lgr.AddHook(func(LogzEntry) (LogzEntry, bool) { return nil, false })
+	if _, ok := recovered(func() { lgr.FatalCtx("dropped", nil) }).(*PanicError); !ok {
+		t.Error("expected FATAL to panic in FatalPanic mode even when dropped by a hook")
+	}
+	if entries := decodeEntries(t, buf); len(entries) != 1 || entries[0].Level != PANIC {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestFatalAndPanicWhenDisabled(t *testing.T) {
+	recovered := func(f func()) (r interface{}) {
+		defer func() { r = recover() }()
+		f()
+		return nil
+	}
+
+	var buf bytes.Buffer
+	lgr := newBufferLogger(&buf)
+	lgr.SetLevel(SILENT)
+	exits := 0
+	lgr.SetExitFunc(func(code int) { exits++ })
+
+	lgr.FatalCtx("fatal", nil)
+	lgr.FatalFields("fatal fields", String("k", "v"))
+	lgr.Emit(&LogEntry{Level: FATAL, Message: "emitted"})
+	if exits != 3 {
+		t.Errorf("expected every disabled FATAL call to exit, got %d exits", exits)
+	}
+	if _, ok := recovered(func() { lgr.PanicCtx("panic", nil) }).(*PanicError); !ok {
+		t.Error("expected a disabled PANIC to panic")
+	}
+	if pe, ok := recovered(func() { lgr.PanicFields("panic fields") }).(*PanicError); !ok || pe.Error() != "panic fields" {
+		t.Errorf("expected a disabled PanicFields to panic, got %#v", pe)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written when SILENT, got %q", buf.String())
+	}
+}
+
+func TestFatalNotifies(t *testing.T) {
+	var buf bytes.Buffer
+	n := &recordNotifier{delay: 20 * time.Millisecond}
+	lgr := newNotifiedLogger(&buf, n)
+
+	var notified []string
+	lgr.SetExitFunc(func(int) { notified = n.messages() })
+	lgr.FatalCtx("boom", nil)
+	if len(notified) != 1 || notified[0] != "boom" {
+		t.Errorf("expected the notifier to receive the FATAL entry before exit, got %v", notified)
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "app.yaml", Err: fs.ErrNotExist}
+	err := fmt.Errorf("loading config: %w", errors.Join(pathErr, errors.New("fallback failed")))
+
+	var buf bytes.Buffer
+	lgr := newBufferLogger(&buf)
+	lgr.SetStackTraceLevel(ERROR)
+	lgr.WithError(err).WarnCtx("degraded", nil)
+	lgr.Named("cfg").WithError(err).ErrorCtx("startup failed", nil)
+	lgr.InfoCtx("no error", nil)
+
+	entries := decodeEntries(t, &buf)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	warn, failed := entries[0].Error, entries[1].Error
+	if warn == nil || len(warn.Stack) != 0 {
+		t.Fatalf("expected an error without stack below the stack trace level, got %+v", warn)
+	}
+	if entries[2].Error != nil {
+		t.Errorf("expected no error on the entry of the parent logger, got %+v", entries[2].Error)
+	}
+	if failed == nil || failed.Type != "*fmt.wrapError" || failed.Message != err.Error() {
+		t.Fatalf("unexpected error: %+v", failed)
+	}
+	joined := failed.Causes[0]
+	if joined.Type != "*errors.joinError" || len(joined.Causes) != 2 ||
+		joined.Causes[0].Type != "*fs.PathError" || joined.Causes[0].Causes[0].Message != fs.ErrNotExist.Error() ||
+		joined.Causes[1].Message != "fallback failed" {
+		t.Errorf("unexpected chain: %+v", joined)
+	}
+	if len(failed.Stack) == 0 || !strings.Contains(failed.Stack[0], "TestErrorFields") {
+		t.Errorf("expected the stack to start at the test, got %v", failed.Stack)
+	}
+
+	text, _ := (&TextFormatter{}).Format(NewLogEntry().WithLevel(ERROR).WithMessage("failed").WithError(err))
+	if !strings.Contains(text, "failed\n  error: loading config: ") ||
+		!strings.Contains(text, "\n      caused by: open app.yaml: file does not exist (*fs.PathError)") {
+		t.Errorf("unexpected text output: %q", text)
+	}
+}
+
+func TestRecoverAndLog(t *testing.T) {
+	out := &recordWriter{}
+	root := NewLogger("test").(*LogzCoreImpl)
+	root.SetWriter(out)
+	dir := t.TempDir()
+	root.SetRecovery(RecoveryConfig{CrashDump: true, CrashDir: dir})
+	worker := root.Named("worker").With(map[string]interface{}{"job": 42})
+
+	func() {
+		defer worker.RecoverAndLog()
+		panic(errors.New("boom"))
+	}()
+
+	worker.Go(func() { panic("in goroutine") })
+	for deadline := time.Now().Add(5 * time.Second); len(out.messages()) < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if got := out.messages(); len(got) != 2 || got[1] != "panic: in goroutine" {
+		t.Fatalf("unexpected entries: %v", got)
+	}
+
+	e := out.entries[0].(*LogEntry)
+	if e.Level != ERROR || e.Message != "panic: boom" || e.Metadata["job"] != 42 || e.Error == nil || e.Source != "test.worker" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if stack, _ := e.Metadata["stack"].(string); !strings.Contains(stack, "TestRecoverAndLog") {
+		t.Errorf("expected the stack of the panicking goroutine, got %q", stack)
+	}
+	crash, _ := e.Metadata["crash_file"].(string)
+	if data, err := os.ReadFile(crash); filepath.Dir(crash) != dir || err != nil || !strings.Contains(string(data), "goroutine ") {
+		t.Errorf("expected a crash dump in %s, got %q (%v)", dir, crash, err)
+	}
+	if path, err := writeCrashDump(dir, `api/v1\..:x`, "boom"); err != nil || filepath.Dir(path) != dir ||
+		!strings.HasPrefix(filepath.Base(path), "crash-api_v1_.._x-") {
+		t.Errorf("expected the logger name to be sanitized in %s, got %q (%v)", dir, path, err)
+	}
+
+	root.SetRecovery(RecoveryConfig{Policy: RecoverRepanic})
+	root.SetExitFunc(func(int) { t.Error("recovery must not exit") })
+	var repanicked interface{}
+	func() {
+		defer func() { repanicked = recover() }()
+		defer worker.RecoverAndLog()
+		panic("again")
+	}()
+	if repanicked != "again" {
+		t.Errorf("expected the value to be panicked again, got %v", repanicked)
+	}
+	if got := out.entries[len(out.entries)-1].(*LogEntry); got.Level != FATAL || got.Message != "panic: again" {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+}
+
+// point is an ObjectMarshaler used by the typed field tests.
+type point struct{ x, y int }
+
+func (p point) MarshalLogObject() []Field {
+	return []Field{Int("x", p.x), Int("y", p.y)}
+}
+
+func TestTypedFields(t *testing.T) {
+	out := &recordWriter{}
+	lgr := NewLogger("test").(*LogzCoreImpl)
+	lgr.SetWriter(out)
+	lgr.SetLevel(DEBUG)
+
+	cause := errors.New("refused")
+	lgr.With(map[string]interface{}{"service": "api"}).Info("request done",
+		String("path", "/users"),
+		Int("status", 200),
+		Float64("ratio", 0.5),
+		Bool("cached", true),
+		Duration("elapsed", 1500*time.Millisecond),
+		Any("ids", []int{1, 2}),
+		Object("origin", point{3, 4}),
+		Err(fmt.Errorf("dial: %w", cause)),
+		String("path", "/users/42"),
+	)
+
+	e := out.entries[0].(*LogEntry)
+	want := map[string]interface{}{
+		"service": "api",
+		"path":    "/users/42",
+		"status":  200,
+		"ratio":   0.5,
+		"cached":  true,
+		"elapsed": 1500 * time.Millisecond,
+		"ids":     []int{1, 2},
+		"origin":  map[string]interface{}{"x": 3, "y": 4},
+	}
+	if e.Level != INFO || e.Message != "request done" || fmt.Sprint(e.Metadata) != fmt.Sprint(want) {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Error == nil || e.Error.Message != "dial: refused" || len(e.Error.Causes) != 1 {
+		t.Errorf("expected the error chain, got %+v", e.Error)
+	}
+	if !strings.Contains(e.Caller, "TestTypedFields") {
+		t.Errorf("expected the caller to be the test, got %q", e.Caller)
+	}
+
+	lgr.SetLevel(ERROR)
+	lgr.Warn("dropped", String("k", "v"))
+	if len(out.entries) != 1 {
+		t.Errorf("expected the disabled level to be dropped, got %d entries", len(out.entries))
+	}
+
+	text := formatMetadata(NewLogEntry().AddMetadata("b", 2).AddMetadata("showContext", true).AddMetadata("a", 1).AddMetadata("c", 3))
+	if !strings.Contains(text, "  - a: 1\n  - b: 2\n  - c: 3\n") {
+		t.Errorf("expected the metadata sorted by key, got %q", text)
+	}
+}
+
+func TestTypedFieldsDisabledDoNotAllocate(t *testing.T) {
+	lgr := NewLogger("test").(*LogzCoreImpl)
+	lgr.SetWriter(NewDefaultWriter[any](io.Discard, &JSONFormatter{}))
+	lgr.SetLevel(ERROR)
+	err := errors.New("boom")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		lgr.Info("request done", String("path", "/users"), Int("status", 200), Duration("elapsed", time.Second), Err(err))
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocation for a disabled level, got %v", allocs)
+	}
+}
+
+func BenchmarkMapFields(b *testing.B) {
+	for _, level := range []LogLevel{ERROR, INFO} {
+		b.Run("level="+string(level), func(b *testing.B) {
+			lgr := NewLogger("bench").(*LogzCoreImpl)
+			lgr.SetWriter(NewDefaultWriter[any](io.Discard, &JSONFormatter{}))
+			lgr.SetLevel(level)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lgr.InfoCtx("request done", map[string]interface{}{
+					"path":    "/users",
+					"status":  200,
+					"elapsed": time.Second,
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkTypedFields(b *testing.B) {
+	for _, level := range []LogLevel{ERROR, INFO} {
+		b.Run("level="+string(level), func(b *testing.B) {
+			lgr := NewLogger("bench").(*LogzCoreImpl)
+			lgr.SetWriter(NewDefaultWriter[any](io.Discard, &JSONFormatter{}))
+			lgr.SetLevel(level)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lgr.Info("request done",
+					String("path", "/users"),
+					Int("status", 200),
+					Duration("elapsed", time.Second),
+				)
+			}
+		})
+	}
+}
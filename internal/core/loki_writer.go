@@ -0,0 +1,273 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lokiPushPath is the push endpoint of the Loki HTTP API.
+const lokiPushPath = "/loki/api/v1/push"
+
+// LokiConfig holds the settings of a LokiWriter.
+type LokiConfig struct {
+	URL         string            // Base URL of Loki, e.g. "http://loki:3100"; the push path is appended when missing.
+	Labels      map[string]string // Static labels of every stream, e.g. {"job": "api"}.
+	LabelKeys   []string          // Metadata keys promoted to stream labels, in addition to the tags.
+	TenantID    string            // Sent as X-Scope-OrgID for multi-tenant deployments.
+	BearerToken string            // Sent as "Authorization: Bearer <token>" when set.
+	Username    string            // Basic auth user, used when BearerToken is empty.
+	Password    string            // Basic auth password.
+	Gzip        bool              // Compress the JSON body with gzip.
+	BatchSize   int               // Maximum number of entries per push (default 100).
+	BatchWait   time.Duration     // Maximum age of a batch before it is pushed (default 1s).
+	QueueSize   int               // Maximum number of queued entries (default 10000).
+	Overflow    OverflowPolicy    // Behavior when the queue is full (default OverflowBlock).
+	Retry       RetryConfig       // Retries of the failed pushes.
+	Timeout     time.Duration     // Timeout of each push request (default 10s).
+	Formatter   LogFormatter      // Formatter of the log lines; defaults to LogfmtFormatter.
+	HTTPClient  *http.Client      // Client of the requests; defaults to a client with Timeout.
+}
+
+// LokiWriter is a LogWriter that batches entries and pushes them to Grafana Loki.
+//
+// Entries are queued and pushed in the background by an AsyncWriter, at the latest
+// BatchWait after the first entry of a batch. Each entry belongs to the stream of its
+// labels: the static Labels, "level", the tags of the entry and the metadata listed in
+// LabelKeys; label names are sanitized to [a-zA-Z0-9_]. Within a stream the entries are
+// sent in timestamp order. Pushes failing with 429 or 5xx are retried with backoff;
+// a batch still failing after the retries is dropped and the error is logged.
+type LokiWriter struct {
+	async  *AsyncWriter
+	cancel context.CancelFunc // Cancels the requests of the client.
+}
+
+// lokiClient pushes batches of entries to Loki; it is the output of the AsyncWriter of a LokiWriter.
+type lokiClient struct {
+	config LokiConfig
+	url    string
+	http   *http.Client
+	ctx    context.Context // Context of the requests, cancelled when Close gives up.
+}
+
+// lokiStream is one stream of a push request.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+
+	times []time.Time
+}
+
+// Len, Less and Swap sort the values of a stream by timestamp.
+func (s *lokiStream) Len() int           { return len(s.Values) }
+func (s *lokiStream) Less(i, j int) bool { return s.times[i].Before(s.times[j]) }
+func (s *lokiStream) Swap(i, j int) {
+	s.Values[i], s.Values[j] = s.Values[j], s.Values[i]
+	s.times[i], s.times[j] = s.times[j], s.times[i]
+}
+
+// NewLokiWriter creates a LokiWriter and starts its background worker.
+func NewLokiWriter(config LokiConfig) (*LokiWriter, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("loki writer requires a URL")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.BatchWait <= 0 {
+		config.BatchWait = time.Second
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10000
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.Formatter == nil {
+		config.Formatter = &LogfmtFormatter{}
+	}
+	config.Retry = config.Retry.withDefaults()
+
+	url := strings.TrimSuffix(config.URL, "/")
+	if !strings.HasSuffix(url, lokiPushPath) {
+		url += lokiPushPath
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &lokiClient{config: config, url: url, http: client, ctx: ctx}
+
+	return &LokiWriter{
+		async: NewAsyncWriter(c, AsyncWriterConfig{
+			QueueSize:     config.QueueSize,
+			Policy:        config.Overflow,
+			BatchSize:     config.BatchSize,
+			FlushInterval: config.BatchWait,
+		}),
+		cancel: cancel,
+	}, nil
+}
+
+// Write queues the entry for the next push.
+func (w *LokiWriter) Write(entry any) error {
+	return w.async.Write(entry)
+}
+
+// Flush pushes every queued entry, giving up when ctx is done.
+func (w *LokiWriter) Flush(ctx context.Context) error {
+	return w.async.Flush(ctx)
+}
+
+// Close pushes the queued entries, retrying as configured by Retry, and stops the background worker.
+// Requests still pending after 5s are cancelled and their entries dropped.
+func (w *LokiWriter) Close() error {
+	return closePushWriter(w.async, w.cancel)
+}
+
+// Dropped returns the number of entries discarded because the queue was full.
+func (w *LokiWriter) Dropped() uint64 {
+	return w.async.Dropped()
+}
+
+// Write pushes a single entry.
+func (c *lokiClient) Write(entry any) error {
+	return c.WriteBatch([]any{entry})
+}
+
+// WriteBatch pushes the entries, grouped by stream, in one request. Entries that cannot be
+// encoded are reported in the returned error; the others are pushed.
+func (c *lokiClient) WriteBatch(entries []any) error {
+	streams, count, encodeErr := c.streams(entries)
+	if len(streams) == 0 {
+		return encodeErr
+	}
+	data, err := json.Marshal(map[string]interface{}{"streams": streams})
+	if err != nil {
+		return errors.Join(encodeErr, err)
+	}
+	if c.config.Gzip {
+		if data, err = gzipBytes(data); err != nil {
+			return errors.Join(encodeErr, err)
+		}
+	}
+	_, err = pushWithRetry(c.ctx, c.http, c.config.Retry, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.config.Gzip {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		if c.config.TenantID != "" {
+			req.Header.Set("X-Scope-OrgID", c.config.TenantID)
+		}
+		setAuthHeader(req, c.config.BearerToken, c.config.Username, c.config.Password)
+		return req, nil
+	})
+	if err != nil {
+		return errors.Join(encodeErr, fmt.Errorf("pushing %d entries to loki: %w", count, err))
+	}
+	return encodeErr
+}
+
+// streams groups the entries by label set, in order of first appearance, each stream
+// sorted by timestamp. It returns the number of entries grouped and the errors of the
+// entries left out.
+func (c *lokiClient) streams(entries []any) ([]*lokiStream, int, error) {
+	var streams []*lokiStream
+	var errs []error
+	count := 0
+	index := make(map[string]*lokiStream)
+	for _, entry := range entries {
+		var e LogzEntry
+		switch v := entry.(type) {
+		case LogzEntry:
+			e = v
+		case []byte:
+			e = NewLogEntry().WithLevel(INFO).WithMessage(string(v))
+		default:
+			errs = append(errs, fmt.Errorf("unsupported log entry type: %T", entry))
+			continue
+		}
+		line, err := c.config.Formatter.Format(e)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		labels := c.labels(e)
+		key := labelsKey(labels)
+		s, ok := index[key]
+		if !ok {
+			s = &lokiStream{Stream: labels}
+			index[key] = s
+			streams = append(streams, s)
+		}
+		ts := e.GetTimestamp()
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(ts.UnixNano(), 10), line})
+		s.times = append(s.times, ts)
+		count++
+	}
+	for _, s := range streams {
+		sort.Stable(s)
+	}
+	return streams, count, errors.Join(errs...)
+}
+
+// labels returns the stream labels of the entry.
+func (c *lokiClient) labels(e LogzEntry) map[string]string {
+	labels := make(map[string]string, len(c.config.Labels)+1)
+	for k, v := range c.config.Labels {
+		labels[lokiLabelName(k)] = v
+	}
+	if level := e.GetLevel(); level != "" {
+		labels["level"] = strings.ToLower(string(level))
+	}
+	if le, ok := e.(*LogEntry); ok {
+		for k, v := range le.Tags {
+			labels[lokiLabelName(k)] = v
+		}
+	}
+	metadata := e.GetMetadata()
+	for _, k := range c.config.LabelKeys {
+		if v, ok := metadata[k]; ok {
+			labels[lokiLabelName(k)] = fmt.Sprintf("%v", normalizeValue(v))
+		}
+	}
+	return labels
+}
+
+// labelsKey returns a string identifying a label set.
+func labelsKey(labels map[string]string) string {
+	var b strings.Builder
+	for _, k := range sortedKeys(labels) {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[k]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// lokiLabelName replaces the characters not allowed in a label name by '_'.
+func lokiLabelName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 {
+		return "_"
+	}
+	return string(name)
+}
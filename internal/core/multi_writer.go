@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrWriteTimeout is returned when a writer of a MultiWriter does not finish within its timeout.
+var ErrWriteTimeout = errors.New("log write timed out")
+
+// LogMultiWriter defines the contract for writers that fan entries out to several writers.
+type LogMultiWriter[T any] interface {
+	Write(entry T) error
+	AddWriter(w LogWriter[T])
+	AddWriterWithOptions(w LogWriter[T], opts WriterOptions)
+	GetWriters() []LogWriter[T]
+}
+
+// WriterOptions configures how a MultiWriter hands entries to one of its writers.
+type WriterOptions struct {
+	MinLevel LogLevel             // Entries below this level are skipped; empty accepts every level.
+	Filter   func(LogzEntry) bool // Optional predicate; entries for which it returns false are skipped.
+	Timeout  time.Duration        // Maximum time to wait for a write; zero waits until it returns.
+}
+
+// multiWriterChild is a writer registered in a MultiWriter with its options.
+type multiWriterChild[T any] struct {
+	writer  LogWriter[T]
+	opts    WriterOptions
+	pending *atomic.Int32 // Number of writes that timed out and are still running.
+}
+
+// MultiWriter writes each entry to several writers concurrently.
+// A failing or slow writer does not prevent the others from receiving the entry:
+// Write waits for every selected writer (or its timeout) and returns their errors joined.
+// The zero value is ready to use.
+type MultiWriter[T any] struct {
+	mu       sync.RWMutex
+	children []multiWriterChild[T]
+}
+
+// NewMultiWriter creates a MultiWriter writing every entry to the given writers.
+func NewMultiWriter[T any](writers ...LogWriter[T]) *MultiWriter[T] {
+	mw := &MultiWriter[T]{}
+	for _, w := range writers {
+		mw.AddWriter(w)
+	}
+	return mw
+}
+
+// AddWriter registers a writer receiving every entry.
+func (mw *MultiWriter[T]) AddWriter(w LogWriter[T]) {
+	mw.AddWriterWithOptions(w, WriterOptions{})
+}
+
+// AddWriterWithOptions registers a writer with its own level, filter and timeout.
+func (mw *MultiWriter[T]) AddWriterWithOptions(w LogWriter[T], opts WriterOptions) {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	mw.children = append(mw.children, multiWriterChild[T]{writer: w, opts: opts, pending: new(atomic.Int32)})
+}
+
+// Write hands the entry to every writer accepting it, concurrently, and waits for all of them.
+// When several writers receive a LogzEntry, or a single one with a Timeout, each gets its
+// own copy, so that a writer modifying or still reading the entry does not race with the
+// others or with the caller. The errors of the writers are wrapped
+// with their index and joined.
+func (mw *MultiWriter[T]) Write(entry T) error {
+	mw.mu.RLock()
+	children := make([]int, 0, len(mw.children))
+	for i, c := range mw.children {
+		if c.accepts(entry) {
+			children = append(children, i)
+		}
+	}
+	snapshot := mw.children
+	mw.mu.RUnlock()
+
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		c := snapshot[children[0]]
+		if c.opts.Timeout > 0 {
+			// A write that times out outlives this call: it must not share the entry with the caller.
+			entry = cloneEntry(entry)
+		}
+		return c.write(children[0], entry)
+	}
+
+	errs := make([]error, len(children))
+	var wg sync.WaitGroup
+	for n, i := range children {
+		wg.Add(1)
+		go func(n, i int) {
+			defer wg.Done()
+			errs[n] = snapshot[i].write(i, cloneEntry(entry))
+		}(n, i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// GetWriters returns the registered writers.
+func (mw *MultiWriter[T]) GetWriters() []LogWriter[T] {
+	mw.mu.RLock()
+	defer mw.mu.RUnlock()
+	writers := make([]LogWriter[T], len(mw.children))
+	for i, c := range mw.children {
+		writers[i] = c.writer
+	}
+	return writers
+}
+
+// Flush flushes every writer implementing LogFlusher and returns their errors joined.
+func (mw *MultiWriter[T]) Flush(ctx context.Context) error {
+	var errs []error
+	for i, w := range mw.GetWriters() {
+		if flusher, ok := any(w).(LogFlusher); ok {
+			if err := flusher.Flush(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("writer %d: %w", i, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every writer implementing io.Closer and returns their errors joined.
+func (mw *MultiWriter[T]) Close() error {
+	var errs []error
+	for i, w := range mw.GetWriters() {
+		if closer, ok := any(w).(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("writer %d: %w", i, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// accepts reports whether the entry passes the level and filter of the writer.
+// Entries that are not a LogzEntry, such as raw bytes, are always accepted.
+func (c multiWriterChild[T]) accepts(entry T) bool {
+	e, ok := any(entry).(LogzEntry)
+	if !ok {
+		return true
+	}
+	if c.opts.MinLevel != "" && logLevels[e.GetLevel()] < logLevels[c.opts.MinLevel] {
+		return false
+	}
+	return c.opts.Filter == nil || c.opts.Filter(e)
+}
+
+// write hands the entry to the writer, giving up after the configured timeout.
+// A write that times out keeps running in the background; until it returns, the next
+// writes fail with ErrWriteTimeout without calling the writer, so that a stuck writer
+// does not pile up goroutines.
+func (c multiWriterChild[T]) write(index int, entry T) error {
+	var err error
+	if c.opts.Timeout <= 0 {
+		err = c.writer.Write(entry)
+	} else if c.pending.Load() > 0 {
+		err = fmt.Errorf("%w: previous write still pending", ErrWriteTimeout)
+	} else {
+		done := make(chan error, 1)
+		go func() { done <- c.writer.Write(entry) }()
+		timer := time.NewTimer(c.opts.Timeout)
+		defer timer.Stop()
+		select {
+		case err = <-done:
+		case <-timer.C:
+			err = ErrWriteTimeout
+			c.pending.Add(1)
+			go func() {
+				<-done
+				c.pending.Add(-1)
+			}()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("writer %d: %w", index, err)
+	}
+	return nil
+}
+
+// cloneEntry returns a copy of the entry when it is a LogzEntry, or the entry itself otherwise.
+func cloneEntry[T any](entry T) T {
+	if e, ok := any(entry).(LogzEntry); ok && e != nil {
+		if clone, ok := e.Clone().(T); ok {
+			return clone
+		}
+	}
+	return entry
+}
@@ -2,11 +2,13 @@ package core
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,6 +20,36 @@ type LogReader interface {
 	Tail(filePath string, stopChan <-chan struct{}) error
 }
 
+// LogParser defines the contract for reading formatted log lines back into entries.
+type LogParser interface {
+	// Parse converts a single formatted line into a log entry.
+	Parse(line string) (LogzEntry, error)
+}
+
+// NewParser returns the parser of the given format, or nil if the format cannot be read back.
+func NewParser(format LogFormat) LogParser {
+	switch LogFormat(strings.ToLower(string(format))) {
+	case JSON:
+		return &JSONParser{}
+	case LOGFMT:
+		return &LogfmtParser{}
+	default:
+		return nil
+	}
+}
+
+// JSONParser reads the lines written by JSONFormatter back into log entries.
+type JSONParser struct{}
+
+// Parse converts a JSON line into a log entry.
+func (p *JSONParser) Parse(line string) (LogzEntry, error) {
+	var le LogEntry
+	if err := json.Unmarshal([]byte(line), &le); err != nil {
+		return nil, err
+	}
+	return &le, nil
+}
+
 // FileLogReader implements the LogReader interface by reading from a file.
 type FileLogReader struct {
 	// pollInterval is the polling interval to check for new lines.
@@ -44,6 +76,60 @@ func NewFileLogReader() *FileLogReader {
 // Tail follows the log file from the end and prints new lines as they are added.
 // The stopChan channel allows interrupting the operation (e.g., via Ctrl+C).
 func (fr *FileLogReader) Tail(filePath string, stopChan <-chan struct{}) error {
+	return fr.tail(filePath, stopChan, func(line string) {
+		// Print the line immediately; can be adapted to send to another channel if needed.
+		fmt.Print(line)
+	})
+}
+
+// TailEntries follows the log file like Tail, parsing each new line and passing the entry to handle.
+// Lines that cannot be parsed are reported and skipped.
+func (fr *FileLogReader) TailEntries(filePath string, parser LogParser, stopChan <-chan struct{}, handle func(LogzEntry)) error {
+	return fr.tail(filePath, stopChan, func(line string) {
+		line = strings.TrimRight(line, "\r\n")
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		entry, err := parser.Parse(line)
+		if err != nil {
+			log.Printf("Skipping unreadable log line: %v", err)
+			return
+		}
+		handle(entry)
+	})
+}
+
+// ReadEntries reads every entry of the log file using the given parser.
+// It stops at the first line that cannot be parsed, reporting its line number.
+func (fr *FileLogReader) ReadEntries(filePath string, parser LogParser) ([]LogzEntry, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []LogzEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		entry, err := parser.Parse(line)
+		if err != nil {
+			return entries, fmt.Errorf("error parsing line %d: %w", lineNo, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("error reading log file: %w", err)
+	}
+	return entries, nil
+}
+
+// tail follows the log file from the end and passes every new line to handle.
+func (fr *FileLogReader) tail(filePath string, stopChan <-chan struct{}, handle func(line string)) error {
 	// Open the log file
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -74,8 +160,7 @@ func (fr *FileLogReader) Tail(filePath string, stopChan <-chan struct{}) error {
 				}
 				return fmt.Errorf("error reading log file: %w", err)
 			}
-			// Print the line immediately; can be adapted to send to another channel if needed.
-			fmt.Print(line)
+			handle(line)
 		}
 	}
 }
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// RecoveryPolicy selects what happens once a recovered panic has been logged.
+type RecoveryPolicy int
+
+const (
+	// RecoverContinue logs the panic at ERROR and lets the goroutine return normally.
+	RecoverContinue RecoveryPolicy = iota
+	// RecoverRepanic logs the panic at FATAL, flushes the writers and panics again with the same value.
+	RecoverRepanic
+)
+
+// RecoveryConfig holds the settings of RecoverAndLog and Go.
+type RecoveryConfig struct {
+	Policy    RecoveryPolicy // What to do after logging (default RecoverContinue).
+	CrashDump bool           // Write the stacks of all goroutines to a crash file.
+	CrashDir  string         // Directory of the crash files; default the directory of the configured Output().
+}
+
+// SetRecovery sets how the panics recovered by RecoverAndLog and Go are handled.
+func (l *LogzCoreImpl) SetRecovery(config RecoveryConfig) {
+	if l.parent != nil {
+		l.root().SetRecovery(config)
+		return
+	}
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+	l.recovery = config
+}
+
+// RecoverAndLog recovers a panic and logs it with the panic value, the stack of the
+// panicking goroutine and the fields bound to l, then applies the recovery policy.
+// It must be deferred directly: defer logger.RecoverAndLog().
+func (l *LogzCoreImpl) RecoverAndLog() {
+	if value := recover(); value != nil {
+		l.HandlePanic(value)
+	}
+}
+
+// Go runs f in a new goroutine whose panics are handled by RecoverAndLog.
+func (l *LogzCoreImpl) Go(f func()) {
+	go func() {
+		defer l.RecoverAndLog()
+		f()
+	}()
+}
+
+// HandlePanic logs a value returned by recover and applies the recovery policy.
+// It is meant for deferred functions that call recover themselves; the stack is only
+// the one of the panicking goroutine when HandlePanic runs in such a deferred function.
+func (l *LogzCoreImpl) HandlePanic(value interface{}) {
+	r := l.root()
+	r.Mu.RLock()
+	config, cfg := r.recovery, r.VConfig
+	r.Mu.RUnlock()
+
+	level := ERROR
+	if config.Policy == RecoverRepanic {
+		level = FATAL
+	}
+	fields := map[string]interface{}{
+		"panic": fmt.Sprint(value),
+		"stack": string(debug.Stack()),
+	}
+	if config.CrashDump {
+		path, err := writeCrashDump(crashDir(config, cfg), l.Name(), value)
+		if err != nil {
+			log.Printf("ErrorCtx writing crash dump: %v", err)
+		} else {
+			fields["crash_file"] = path
+		}
+	}
+
+	if l.Enabled(level) {
+		entry := l.newEntry(context.Background(), level, fmt.Sprintf("panic: %v", value), fields)
+		if err, ok := value.(error); ok {
+			entry.WithError(err)
+		}
+		// The entry is delivered without terminate: the policy decides how the goroutine ends.
+		l.deliver(entry)
+	}
+
+	if config.Policy == RecoverRepanic {
+		ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+		if err := r.Flush(ctx); err != nil {
+			log.Printf("ErrorCtx flushing logs: %v", err)
+		}
+		cancel()
+		panic(value)
+	}
+}
+
+// crashDir returns the directory of the crash files: the configured one, the directory
+// of the log file, or the temporary directory when the logs go to the console.
+func crashDir(config RecoveryConfig, cfg Config) string {
+	if config.CrashDir != "" {
+		return config.CrashDir
+	}
+	if cfg != nil {
+		out := cfg.Output()
+		switch strings.ToLower(out) {
+		case "", "stdout", "stderr", os.Stdout.Name(), os.Stderr.Name():
+		default:
+			return filepath.Dir(out)
+		}
+	}
+	return os.TempDir()
+}
+
+// writeCrashDump writes the panic value and the stacks of all goroutines to a new file in dir.
+func writeCrashDump(dir, name string, value interface{}) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%s-%d.log", crashFileName(name), now.Format("20060102T150405.000"), os.Getpid()))
+	content := fmt.Sprintf("panic: %v\ntime: %s\npid: %d\n\n%s", value, now.Format(time.RFC3339Nano), os.Getpid(), allStacks())
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// crashFileName returns the logger name as a file name component: path separators and
+// the characters unsafe in file names are replaced with underscores.
+func crashFileName(name string) string {
+	if name == "" {
+		return "logz"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// allStacks returns the stacks of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
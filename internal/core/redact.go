@@ -0,0 +1,297 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// redactedText is the text written in place of redacted values.
+const redactedText = "***"
+
+// Secret is a string value that never appears in the logs.
+// It renders as "***" in every formatter, in fmt verbs and through slog.
+type Secret string
+
+// String returns the redacted text.
+func (s Secret) String() string { return redactedText }
+
+// GoString returns the redacted text for the %#v verb.
+func (s Secret) GoString() string { return redactedText }
+
+// Format renders the redacted text for every fmt verb.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = fmt.Fprintf(f, "%q", redactedText)
+		return
+	}
+	_, _ = io.WriteString(f, redactedText)
+}
+
+// MarshalJSON renders the redacted text as a JSON string.
+func (s Secret) MarshalJSON() ([]byte, error) { return []byte(`"` + redactedText + `"`), nil }
+
+// MarshalText renders the redacted text.
+func (s Secret) MarshalText() ([]byte, error) { return []byte(redactedText), nil }
+
+// LogValue renders the redacted text through slog.
+func (s Secret) LogValue() slog.Value { return slog.StringValue(redactedText) }
+
+// Value returns the secret itself, for the code that needs it.
+func (s Secret) Value() string { return string(s) }
+
+// RedactPattern masks the parts of strings matching a regular expression.
+type RedactPattern struct {
+	Name        string                  // Name of the pattern, e.g. "email".
+	Regexp      *regexp.Regexp          // Expression matching the sensitive text.
+	Validate    func(match string) bool // Optional check of each match; matches failing it are kept.
+	Replacement string                  // Replacement of each match; "$1" expands groups. Default "***".
+}
+
+// RedactEmails masks e-mail addresses.
+func RedactEmails() RedactPattern {
+	return RedactPattern{
+		Name:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+}
+
+// RedactJWTs masks JSON Web Tokens.
+func RedactJWTs() RedactPattern {
+	return RedactPattern{
+		Name:   "jwt",
+		Regexp: regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`),
+	}
+}
+
+// RedactBearerTokens masks the token of "Bearer <token>" credentials.
+func RedactBearerTokens() RedactPattern {
+	return RedactPattern{
+		Name:        "bearer",
+		Regexp:      regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		Replacement: "${1}" + redactedText,
+	}
+}
+
+// RedactCardNumbers masks payment card numbers, optionally grouped with spaces or dashes.
+// Only digit sequences passing the Luhn check are masked.
+func RedactCardNumbers() RedactPattern {
+	return RedactPattern{
+		Name:     "card",
+		Regexp:   regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+		Validate: luhnValid,
+	}
+}
+
+// Redactor masks sensitive data in log entries.
+//
+// Values of metadata keys and tags containing one of Keys (case-insensitively) are replaced
+// as a whole, including nested keys. The patterns run over the message, the context, the
+// tag values, the error messages and every string in the metadata, nested maps and slices
+// included. Values of other types are kept as is; wrap them in Secret when they must not
+// be logged.
+type Redactor struct {
+	Keys     []string        // Key names whose values are masked, e.g. "password".
+	Patterns []RedactPattern // Patterns masked inside strings.
+}
+
+// DefaultRedactor returns a Redactor masking the usual credential keys, e-mail addresses,
+// JWTs, bearer tokens and card numbers.
+func DefaultRedactor() *Redactor {
+	return &Redactor{
+		Keys: []string{
+			"password", "passwd", "secret", "token", "authorization",
+			"api_key", "apikey", "cookie", "credential", "private_key",
+		},
+		Patterns: []RedactPattern{
+			RedactEmails(),
+			RedactJWTs(),
+			RedactBearerTokens(),
+			RedactCardNumbers(),
+		},
+	}
+}
+
+// Redact returns a redacted copy of the entry; the entry itself is left untouched.
+func (r *Redactor) Redact(entry LogzEntry) LogzEntry {
+	redacted := entry.Clone()
+	redacted.WithMessage(r.redactString(entry.GetMessage()))
+	if c := entry.GetContext(); c != "" {
+		redacted.WithContext(r.redactString(c))
+	}
+	metadata := redacted.GetMetadata()
+	for k, v := range metadata {
+		metadata[k] = r.redactField(k, v)
+	}
+	if le, ok := redacted.(*LogEntry); ok {
+		for k, v := range le.Tags {
+			if r.sensitiveKey(k) {
+				le.Tags[k] = redactedText
+			} else {
+				le.Tags[k] = r.redactString(v)
+			}
+		}
+		if le.Error != nil {
+			info := r.redactError(*le.Error)
+			le.Error = &info
+		}
+	}
+	return redacted
+}
+
+// redactError returns a copy of the error with its message and those of its causes redacted.
+func (r *Redactor) redactError(info ErrorInfo) ErrorInfo {
+	info.Message = r.redactString(info.Message)
+	if len(info.Causes) > 0 {
+		causes := make([]ErrorInfo, len(info.Causes))
+		for i, cause := range info.Causes {
+			causes[i] = r.redactError(cause)
+		}
+		info.Causes = causes
+	}
+	return info
+}
+
+// Hook returns a hook redacting every entry of a logger before it reaches the writers.
+func (r *Redactor) Hook() Hook {
+	return func(entry LogzEntry) (LogzEntry, bool) {
+		return r.Redact(entry), true
+	}
+}
+
+// redactField masks the value of a metadata key.
+func (r *Redactor) redactField(key string, v interface{}) interface{} {
+	if r.sensitiveKey(key) {
+		return Secret("")
+	}
+	return r.redactValue(v)
+}
+
+// redactValue masks the strings found in v, copying the maps and slices it goes through.
+func (r *Redactor) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return r.redactString(val)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, nested := range val {
+			m[k] = r.redactField(k, nested)
+		}
+		return m
+	case map[string]string:
+		m := make(map[string]interface{}, len(val))
+		for k, nested := range val {
+			m[k] = r.redactField(k, nested)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = r.redactValue(item)
+		}
+		return s
+	case []string:
+		s := make([]string, len(val))
+		for i, item := range val {
+			s[i] = r.redactString(item)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// sensitiveKey reports whether the key contains one of the configured key names.
+func (r *Redactor) sensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, k := range r.Keys {
+		if k != "" && strings.Contains(lower, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString applies every pattern to s.
+func (r *Redactor) redactString(s string) string {
+	for _, p := range r.Patterns {
+		if p.Regexp == nil {
+			continue
+		}
+		replacement := p.Replacement
+		if replacement == "" {
+			replacement = redactedText
+		}
+		s = p.Regexp.ReplaceAllStringFunc(s, func(match string) string {
+			if p.Validate != nil && !p.Validate(match) {
+				return match
+			}
+			return p.Regexp.ReplaceAllString(match, replacement)
+		})
+	}
+	return s
+}
+
+// luhnValid reports whether the digits of s form a 13 to 19 digit number passing the Luhn check.
+func luhnValid(s string) bool {
+	var digits []int
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// RedactingWriter is a LogWriter that redacts the entries before handing them to another writer.
+// Wrapping each writer of a MultiWriter with its own Redactor gives every sink its own profile.
+type RedactingWriter struct {
+	out      LogWriter[any]
+	redactor *Redactor
+}
+
+// NewRedactingWriter creates a RedactingWriter writing to out.
+func NewRedactingWriter(out LogWriter[any], redactor *Redactor) *RedactingWriter {
+	return &RedactingWriter{out: out, redactor: redactor}
+}
+
+// Write redacts the entry and writes the redacted copy.
+func (w *RedactingWriter) Write(entry any) error {
+	if e, ok := entry.(LogzEntry); ok && w.redactor != nil {
+		return w.out.Write(w.redactor.Redact(e))
+	}
+	return w.out.Write(entry)
+}
+
+// Flush flushes the wrapped writer when it implements LogFlusher.
+func (w *RedactingWriter) Flush(ctx context.Context) error {
+	if flusher, ok := w.out.(LogFlusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
+
+// Close closes the wrapped writer when it implements io.Closer.
+func (w *RedactingWriter) Close() error {
+	if closer, ok := w.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
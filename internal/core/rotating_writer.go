@@ -0,0 +1,347 @@
+package core
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotationInterval defines the time schedule of a RotatingFileWriter.
+type RotationInterval string
+
+const (
+	RotateNever  RotationInterval = ""       // Rotate on size only
+	RotateHourly RotationInterval = "hourly" // Rotate at the start of every hour
+	RotateDaily  RotationInterval = "daily"  // Rotate at local midnight
+)
+
+// backupTimeLayout is the timestamp embedded in the name of rotated files.
+const backupTimeLayout = "20060102T150405.000"
+
+// RotatingFileConfig holds the settings of a RotatingFileWriter.
+type RotatingFileConfig struct {
+	Filename   string           // Path of the active log file.
+	MaxSize    int64            // Size in bytes that triggers a rotation; zero disables size rotation.
+	Interval   RotationInterval // Time schedule that triggers a rotation.
+	MaxBackups int              // Number of rotated files to keep; zero keeps all of them.
+	MaxAge     time.Duration    // Age after which rotated files are removed; zero keeps them forever.
+	Compress   bool             // Compress rotated files with gzip in the background.
+	Formatter  LogFormatter     // Formatter of the entries; defaults to TextFormatter.
+}
+
+// RotatingFileWriter is a LogWriter that writes to a file and rotates it in-process.
+// Rotated files are renamed to "<name>-<timestamp><ext>" next to the active file, so
+// the writer never keeps a descriptor to a file that has been moved away.
+type RotatingFileWriter struct {
+	config RotatingFileConfig
+
+	mu           sync.Mutex
+	file         *os.File
+	size         int64
+	nextRotation time.Time
+	closed       bool
+
+	millMu sync.Mutex     // serializes compression and cleanup of rotated files
+	millWg sync.WaitGroup // tracks the background compression and cleanup
+}
+
+// NewRotatingFileWriter creates a RotatingFileWriter, opening or creating the configured file.
+func NewRotatingFileWriter(config RotatingFileConfig) (*RotatingFileWriter, error) {
+	if config.Filename == "" {
+		return nil, fmt.Errorf("rotating file writer requires a file name")
+	}
+	if config.Formatter == nil {
+		config.Formatter = &TextFormatter{}
+	}
+	w := &RotatingFileWriter{config: config}
+	if err := w.openFile(time.Now()); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write formats the entry and appends it to the file, rotating it first when needed.
+func (w *RotatingFileWriter) Write(entry any) error {
+	var formatted string
+	var err error
+
+	switch v := entry.(type) {
+	case LogzEntry:
+		formatted, err = w.config.Formatter.Format(v)
+	case []byte:
+		formatted, err = w.config.Formatter.Format(NewLogEntry().WithMessage(string(v)))
+	default:
+		return fmt.Errorf("unsupported log entry type: %T", entry)
+	}
+	if err != nil {
+		return err
+	}
+	line := formatted + "\n"
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	// A failed rotation is reported, but the line is still written to the current file.
+	now := time.Now()
+	var rotateErr error
+	if w.shouldRotate(now, int64(len(line))) {
+		rotateErr = w.rotate(now)
+	}
+
+	n, err := w.file.WriteString(line)
+	w.size += int64(n)
+	return errors.Join(rotateErr, err)
+}
+
+// Rotate forces a rotation of the active file.
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.rotate(time.Now())
+}
+
+// Flush commits the content of the active file to stable storage.
+func (w *RotatingFileWriter) Flush(_ context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close closes the active file and waits for the background compression to finish.
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	w.mu.Unlock()
+
+	w.millWg.Wait()
+	return err
+}
+
+// shouldRotate reports whether the file must be rotated before writing n more bytes.
+func (w *RotatingFileWriter) shouldRotate(now time.Time, n int64) bool {
+	if w.config.MaxSize > 0 && w.size > 0 && w.size+n > w.config.MaxSize {
+		return true
+	}
+	return !w.nextRotation.IsZero() && !now.Before(w.nextRotation)
+}
+
+// rotate renames the active file and opens a new one. The caller must hold w.mu.
+//
+// The current file stays open until its replacement is, so that a failed rotation keeps
+// writing to it rather than losing entries; the next attempt is then postponed to the next
+// MaxSize bytes or interval. When the active file was removed or moved away, e.g. by
+// logrotate, a new one is opened under Filename.
+func (w *RotatingFileWriter) rotate(now time.Time) error {
+	old := w.file
+	backup := w.backupName(now)
+	if err := os.Rename(w.config.Filename, backup); err != nil {
+		err = fmt.Errorf("error renaming the log file: %w", err)
+		if errors.Is(err, fs.ErrNotExist) {
+			if openErr := w.openFile(now); openErr == nil {
+				_ = old.Close()
+				return err
+			}
+		}
+		w.postponeRotation(now)
+		return err
+	}
+	if err := w.openFile(now); err != nil {
+		w.postponeRotation(now)
+		return err
+	}
+	if err := old.Close(); err != nil {
+		log.Printf("ErrorCtx closing the rotated log file: %v", err)
+	}
+
+	w.millWg.Add(1)
+	go w.mill(backup)
+	return nil
+}
+
+// postponeRotation restarts the size and time triggers after a failed rotation, so that
+// it is not attempted again on every write. The caller must hold w.mu.
+func (w *RotatingFileWriter) postponeRotation(now time.Time) {
+	w.size = 0
+	w.nextRotation = nextRotationTime(w.config.Interval, now)
+}
+
+// openFile opens the configured file for appending and schedules the next timed rotation.
+func (w *RotatingFileWriter) openFile(now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(w.config.Filename), 0755); err != nil {
+		return fmt.Errorf("error creating the log directory: %w", err)
+	}
+	f, err := os.OpenFile(w.config.Filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening the log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error getting file information: %w", err)
+	}
+	w.file = f
+	w.size = info.Size()
+	w.nextRotation = nextRotationTime(w.config.Interval, now)
+	return nil
+}
+
+// backupName returns an unused name for a file rotated at the given time.
+func (w *RotatingFileWriter) backupName(now time.Time) string {
+	dir, prefix, ext := w.nameParts()
+	stamp := now.Format(backupTimeLayout)
+	name := filepath.Join(dir, fmt.Sprintf("%s%s%s", prefix, stamp, ext))
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = filepath.Join(dir, fmt.Sprintf("%s%s.%d%s", prefix, stamp, i, ext))
+	}
+	return name
+}
+
+// nameParts splits the configured file name into its directory, backup prefix and extension.
+func (w *RotatingFileWriter) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(w.config.Filename)
+	base := filepath.Base(w.config.Filename)
+	ext = filepath.Ext(base)
+	prefix = strings.TrimSuffix(base, ext) + "-"
+	return dir, prefix, ext
+}
+
+// mill compresses a freshly rotated file and removes the backups exceeding MaxBackups or MaxAge.
+func (w *RotatingFileWriter) mill(backup string) {
+	defer w.millWg.Done()
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	if w.config.Compress {
+		if err := compressFile(backup); err != nil {
+			log.Printf("ErrorCtx compressing rotated log file: %v", err)
+		}
+	}
+	if err := w.removeOldBackups(time.Now()); err != nil {
+		log.Printf("ErrorCtx removing old log files: %v", err)
+	}
+}
+
+// removeOldBackups deletes the rotated files beyond the configured count and age.
+func (w *RotatingFileWriter) removeOldBackups(now time.Time) error {
+	if w.config.MaxBackups <= 0 && w.config.MaxAge <= 0 {
+		return nil
+	}
+	dir, prefix, ext := w.nameParts()
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	type backupFile struct {
+		path    string
+		rotated time.Time
+	}
+	var backups []backupFile
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".gz")
+		if f.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if len(stamp) > len(backupTimeLayout) {
+			stamp = stamp[:len(backupTimeLayout)]
+		}
+		rotated, parseErr := time.ParseInLocation(backupTimeLayout, stamp, time.Local)
+		if parseErr != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, f.Name()), rotated: rotated})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.After(backups[j].rotated) })
+
+	var errs []string
+	for i, b := range backups {
+		expired := w.config.MaxAge > 0 && now.Sub(b.rotated) > w.config.MaxAge
+		exceeding := w.config.MaxBackups > 0 && i >= w.config.MaxBackups
+		if expired || exceeding {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// nextRotationTime returns the next time boundary of the interval, or zero for RotateNever.
+func nextRotationTime(interval RotationInterval, now time.Time) time.Time {
+	switch interval {
+	case RotateHourly:
+		// Truncate works on absolute time, which is off by the zone offset in zones
+		// not aligned to the hour; the boundary is computed on the local clock instead.
+		y, m, d := now.Date()
+		return time.Date(y, m, d, now.Hour()+1, 0, 0, 0, now.Location())
+	case RotateDaily:
+		y, m, d := now.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// compressFile gzips the file into "<path>.gz" and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening the file: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating the gzip file: %w", err)
+	}
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		_ = gw.Close()
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return fmt.Errorf("error compressing the file: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("error compressing the file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("error closing the gzip file: %w", err)
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+// fileExists reports whether a file exists at the given path.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingKey selects how a Sampler groups entries.
+type SamplingKey int
+
+const (
+	// SampleByMessage groups entries by level and message.
+	SampleByMessage SamplingKey = iota
+	// SampleByCaller groups entries by level and call site.
+	SampleByCaller
+)
+
+// SamplingRule keeps the First entries of each group per tick, then every Thereafter-th one.
+// A rule with both values set to zero disables sampling; Thereafter set to zero drops
+// every entry after the First ones.
+type SamplingRule struct {
+	First      int
+	Thereafter int
+}
+
+// SamplingConfig holds the settings of a Sampler.
+type SamplingConfig struct {
+	Tick   time.Duration             // Length of a sampling window (default 1s).
+	Key    SamplingKey               // How entries are grouped (default SampleByMessage).
+	Rule   SamplingRule              // Rule of the levels missing from Levels.
+	Levels map[LogLevel]SamplingRule // Per-level rules.
+}
+
+// Sampler limits the number of identical entries logged per tick.
+// PANIC and FATAL entries are never sampled out.
+type Sampler struct {
+	config SamplingConfig
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+	sampled     map[LogLevel]uint64 // entries sampled out in the current window, per level
+
+	dropped atomic.Uint64
+}
+
+// NewSampler creates a Sampler with the given settings.
+func NewSampler(config SamplingConfig) *Sampler {
+	if config.Tick <= 0 {
+		config.Tick = time.Second
+	}
+	return &Sampler{
+		config:  config,
+		counts:  make(map[string]int),
+		sampled: make(map[LogLevel]uint64),
+	}
+}
+
+// Sample reports whether the entry must be logged, counting it in its group.
+func (s *Sampler) Sample(entry LogzEntry) bool {
+	level := entry.GetLevel()
+	if level == PANIC || level == FATAL {
+		return true
+	}
+	rule, ok := s.config.Levels[level]
+	if !ok {
+		rule = s.config.Rule
+	}
+	if rule.First <= 0 && rule.Thereafter <= 0 {
+		return true
+	}
+	key := string(level) + "\x00" + s.groupKey(entry)
+
+	now := time.Now()
+	s.mu.Lock()
+	var report map[LogLevel]uint64
+	if now.Sub(s.windowStart) >= s.config.Tick {
+		report = s.resetWindow(now)
+	}
+	s.counts[key]++
+	n := s.counts[key]
+	keep := n <= rule.First || (rule.Thereafter > 0 && (n-rule.First)%rule.Thereafter == 0)
+	if !keep {
+		s.sampled[level]++
+	}
+	s.mu.Unlock()
+
+	if !keep {
+		s.dropped.Add(1)
+	}
+	reportSampled(report)
+	return keep
+}
+
+// Flush reports the entries sampled out in the current window without waiting for it to end,
+// so that the drops of a last burst are not lost when the traffic stops or the process exits.
+// The logger flushes its sampler in Flush and Close.
+func (s *Sampler) Flush(_ context.Context) error {
+	s.mu.Lock()
+	var report map[LogLevel]uint64
+	if len(s.sampled) > 0 {
+		report = s.sampled
+		s.sampled = make(map[LogLevel]uint64)
+	}
+	s.mu.Unlock()
+	reportSampled(report)
+	return nil
+}
+
+// Dropped returns the number of entries sampled out since the Sampler was created.
+func (s *Sampler) Dropped() uint64 { return s.dropped.Load() }
+
+// groupKey returns the part of the key identifying the group of the entry.
+func (s *Sampler) groupKey(entry LogzEntry) string {
+	if s.config.Key == SampleByCaller {
+		if le, ok := entry.(*LogEntry); ok {
+			return le.Caller
+		}
+	}
+	return entry.GetMessage()
+}
+
+// resetWindow starts a new window and returns the counts of the previous one. The caller must hold s.mu.
+func (s *Sampler) resetWindow(now time.Time) map[LogLevel]uint64 {
+	var report map[LogLevel]uint64
+	if len(s.sampled) > 0 {
+		report = s.sampled
+		s.sampled = make(map[LogLevel]uint64)
+	}
+	s.windowStart = now
+	clear(s.counts)
+	return report
+}
+
+// reportSampled exports the entries sampled out in a window to the PrometheusManager.
+// It is called once per window, or on Flush, since updating the metrics is costly.
+func reportSampled(sampled map[LogLevel]uint64) {
+	if len(sampled) == 0 {
+		return
+	}
+	pm := GetPrometheusManager()
+	if !pm.IsEnabled() {
+		return
+	}
+	var total uint64
+	for level, n := range sampled {
+		total += n
+		pm.IncrementMetric("logs_sampled_total_"+string(level), float64(n))
+	}
+	pm.IncrementMetric("logs_sampled_total", float64(total))
+}
+
+// SetSampler sets the sampler applied to the entries of the logger and its children; nil disables sampling.
+func (l *LogzCoreImpl) SetSampler(sampler *Sampler) {
+	if l.parent != nil {
+		l.root().SetSampler(sampler)
+		return
+	}
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+	l.sampler = sampler
+}
+
+// getSampler returns the sampler of the root logger, if any.
+func (l *LogzCoreImpl) getSampler() *Sampler {
+	r := l.root()
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	return r.sampler
+}
@@ -70,6 +70,7 @@ func Run() error {
 	if err := registerHandlers(mux); err != nil {
 		return err
 	}
+	RegisterAdminHandlers(mux, globalLogger, AdminConfig{Token: os.Getenv("LOGZ_ADMIN_TOKEN")})
 
 	lSrv = &http.Server{
 		Addr:         config.Address(),
@@ -348,11 +349,22 @@ func shutdown() error {
 }
 
 // initializeGlobalLogger initializes the global core with the provided configuration.
+// The caller must hold mu.
 func initializeGlobalLogger(config Config) {
-	mu.Lock()
-	defer mu.Unlock()
-
 	if globalLogger == nil {
 		globalLogger = NewLogger("Logz")
+		// Under systemd, send structured entries to the journal instead of colored text.
+		if JournalStreamConnected() {
+			if w, err := NewJournaldWriter(JournaldConfig{Identifier: "logz"}); err == nil {
+				globalLogger.SetWriter(w)
+			} else {
+				log.Printf("ErrorCtx connecting to journald: %v", err)
+			}
+		}
+	}
+	if config != nil {
+		for module, level := range config.ModuleLevels() {
+			globalLogger.SetModuleLevel(module, level)
+		}
 	}
 }
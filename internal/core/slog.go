@@ -0,0 +1,228 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Custom slog levels for the logz levels that have no slog counterpart.
+// They are ordered to match the logz severities: DEBUG < TRACE < INFO < NOTICE < SUCCESS < WARN < ERROR.
+// PANIC and FATAL have no slog level: a slog record never panics or exits.
+const (
+	SlogLevelTrace   slog.Level = -2
+	SlogLevelNotice  slog.Level = 1
+	SlogLevelSuccess slog.Level = 2
+)
+
+// SlogHandler is a slog.Handler that routes records through the logz pipeline.
+// Record attributes become entry VMetadata and groups become nested maps.
+type SlogHandler struct {
+	logger LogzLogger
+	attrs  map[string]interface{} // attributes added by WithAttrs, already nested under their groups
+	groups []string               // groups opened by WithGroup
+}
+
+// NewSlogHandler creates a slog.Handler writing through the given logger.
+func NewSlogHandler(logger LogzLogger) *SlogHandler {
+	return &SlogHandler{
+		logger: logger,
+		attrs:  make(map[string]interface{}),
+	}
+}
+
+// NewSlogLogger creates a *slog.Logger writing through the given logger.
+func NewSlogLogger(logger LogzLogger) *slog.Logger {
+	return slog.New(NewSlogHandler(logger))
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Enabled(logLevelFromSlog(level))
+}
+
+// Handle converts the record into a LogzEntry and emits it through the logger.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	level := logLevelFromSlog(record.Level)
+
+	metadata := contextFields(ctx)
+	for k, v := range h.attrs {
+		metadata[k] = v
+	}
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	addSlogAttrs(metadata, h.groups, attrs)
+
+	entry := NewLogEntry().
+		WithLevel(level).
+		WithMessage(record.Message).
+		WithSeverity(logLevels[level]).
+		WithTraceID(TraceIDFromContext(ctx))
+	if !record.Time.IsZero() {
+		entry.WithTimestamp(record.Time)
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		entry.WithCaller(fmt.Sprintf("%s:%d %s", trimFilePath(frame.File), frame.Line, frame.Function))
+	}
+	for k, v := range metadata {
+		entry.AddMetadata(k, v)
+	}
+
+	h.logger.Emit(entry)
+	return nil
+}
+
+// WithAttrs returns a handler whose records carry the given attributes.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	c := h.clone()
+	addSlogAttrs(c.attrs, c.groups, attrs)
+	return c
+}
+
+// WithGroup returns a handler nesting the attributes that follow under the given group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := h.clone()
+	c.groups = append(c.groups, name)
+	return c
+}
+
+// clone returns a copy of the handler that can be modified without affecting h.
+func (h *SlogHandler) clone() *SlogHandler {
+	groups := make([]string, len(h.groups))
+	copy(groups, h.groups)
+	return &SlogHandler{
+		logger: h.logger,
+		attrs:  cloneAlong(h.attrs, groups),
+		groups: groups,
+	}
+}
+
+// addSlogAttrs adds the attributes to dst under the nested maps named by groups.
+// Nested maps along the path are copied first, so maps shared with other handlers stay untouched.
+func addSlogAttrs(dst map[string]interface{}, groups []string, attrs []slog.Attr) {
+	target := dst
+	for _, g := range groups {
+		next, ok := target[g].(map[string]interface{})
+		if ok {
+			next = cloneAlong(next, nil)
+		} else {
+			next = make(map[string]interface{})
+		}
+		target[g] = next
+		target = next
+	}
+	for _, a := range attrs {
+		addSlogAttr(target, a)
+	}
+	pruneEmptyGroups(dst, groups)
+}
+
+// addSlogAttr adds a single attribute to dst following the slog handler rules.
+func addSlogAttr(dst map[string]interface{}, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		dst[a.Key] = slogValue(a.Value)
+		return
+	}
+	members := a.Value.Group()
+	if len(members) == 0 {
+		return
+	}
+	target := dst
+	if a.Key != "" {
+		nested, ok := dst[a.Key].(map[string]interface{})
+		if ok {
+			nested = cloneAlong(nested, nil)
+		} else {
+			nested = make(map[string]interface{})
+		}
+		dst[a.Key] = nested
+		target = nested
+	}
+	for _, m := range members {
+		addSlogAttr(target, m)
+	}
+}
+
+// slogValue converts a resolved slog.Value into a value suited for the logz formatters.
+func slogValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	default:
+		return v.Any()
+	}
+}
+
+// cloneAlong copies m and every nested map on the given path.
+func cloneAlong(m map[string]interface{}, path []string) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	if len(path) > 0 {
+		if nested, ok := c[path[0]].(map[string]interface{}); ok {
+			c[path[0]] = cloneAlong(nested, path[1:])
+		}
+	}
+	return c
+}
+
+// pruneEmptyGroups removes the maps on the given path that ended up without attributes.
+func pruneEmptyGroups(m map[string]interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	nested, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	pruneEmptyGroups(nested, path[1:])
+	if len(nested) == 0 {
+		delete(m, path[0])
+	}
+}
+
+// logLevelFromSlog maps a slog level onto the closest logz LogLevel.
+func logLevelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < SlogLevelTrace:
+		return DEBUG
+	case level < slog.LevelInfo:
+		return TRACE
+	case level < SlogLevelNotice:
+		return INFO
+	case level < SlogLevelSuccess:
+		return NOTICE
+	case level < slog.LevelWarn:
+		return SUCCESS
+	case level < slog.LevelError:
+		return WARN
+	default:
+		// A slog handler must never terminate the process: the levels above
+		// slog.LevelError, custom ones included, are logged as ERROR.
+		return ERROR
+	}
+}
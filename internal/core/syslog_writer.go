@@ -0,0 +1,442 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogFormat selects the header layout of the messages sent by a SyslogWriter.
+type SyslogFormat string
+
+const (
+	SyslogRFC5424 SyslogFormat = "rfc5424" // <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+	SyslogRFC3164 SyslogFormat = "rfc3164" // <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+)
+
+// SyslogFacility is the facility code of the messages sent by a SyslogWriter.
+type SyslogFacility int
+
+const (
+	FacilityUser     SyslogFacility = 1  // User-level messages
+	FacilityMail     SyslogFacility = 2  // Mail system
+	FacilityDaemon   SyslogFacility = 3  // System daemons
+	FacilityAuth     SyslogFacility = 4  // Security/authorization messages
+	FacilitySyslog   SyslogFacility = 5  // Messages generated internally by syslogd
+	FacilityLPR      SyslogFacility = 6  // Line printer subsystem
+	FacilityNews     SyslogFacility = 7  // Network news subsystem
+	FacilityUUCP     SyslogFacility = 8  // UUCP subsystem
+	FacilityCron     SyslogFacility = 9  // Clock daemon
+	FacilityAuthPriv SyslogFacility = 10 // Security/authorization messages (private)
+	FacilityFTP      SyslogFacility = 11 // FTP daemon
+	FacilityLocal0   SyslogFacility = 16 // Local use 0
+	FacilityLocal1   SyslogFacility = 17 // Local use 1
+	FacilityLocal2   SyslogFacility = 18 // Local use 2
+	FacilityLocal3   SyslogFacility = 19 // Local use 3
+	FacilityLocal4   SyslogFacility = 20 // Local use 4
+	FacilityLocal5   SyslogFacility = 21 // Local use 5
+	FacilityLocal6   SyslogFacility = 22 // Local use 6
+	FacilityLocal7   SyslogFacility = 23 // Local use 7
+)
+
+// syslogSeverities maps the logz levels to the syslog severities of RFC 5424.
+var syslogSeverities = map[LogLevel]int{
+	DEBUG:   7, // debug
+	TRACE:   7, // debug
+	INFO:    6, // informational
+	NOTICE:  5, // notice
+	SUCCESS: 5, // notice
+	WARN:    4, // warning
+	ERROR:   3, // error
+	PANIC:   2, // critical
+	FATAL:   1, // alert
+}
+
+// syslogLocalSockets are the paths tried, in order, when no address is configured.
+var syslogLocalSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// defaultStructuredDataID is the SD-ID of the metadata element, under the example
+// enterprise number reserved by RFC 5612.
+const defaultStructuredDataID = "logz@32473"
+
+// SyslogConfig holds the settings of a SyslogWriter.
+type SyslogConfig struct {
+	Network          string         // "unixgram" or "unix" for a local socket, "udp", "tcp" or "tls"; empty selects the local socket.
+	Address          string         // Socket path or host:port; empty selects /dev/log, /var/run/syslog or /var/run/log.
+	Format           SyslogFormat   // Header layout; default RFC 3164 for a local socket, RFC 5424 otherwise.
+	Facility         SyslogFacility // Facility of the messages; zero selects FacilityUser.
+	AppName          string         // APP-NAME, or TAG in RFC 3164; defaults to the name of the executable.
+	StructuredDataID string         // SD-ID of the metadata element (default "logz@32473").
+	TLSConfig        *tls.Config    // TLS settings of the "tls" network.
+	NewlineFraming   bool           // Terminate messages with LF instead of an octet count on TCP and TLS; always used on a unix stream socket.
+	Timeout          time.Duration  // Timeout of the connection and of each write (default 5s).
+	Formatter        LogFormatter   // Formatter of the MSG part; defaults to the message followed by the metadata in RFC 3164.
+}
+
+// SyslogWriter is a LogWriter that sends entries to a syslog daemon, locally through
+// /dev/log or remotely over UDP, TCP or TLS.
+//
+// The logz levels are mapped to the syslog severities (TRACE to debug, NOTICE and SUCCESS
+// to notice, PANIC to critical, FATAL to alert), the Hostname and ProcessID of the entry
+// fill the header and the source of the entry is the MSGID. In RFC 5424 the metadata, the
+// tags and the trace ID are sent as the parameters of one structured-data element; nested
+// maps are flattened into dotted names. Messages on TCP and TLS use the octet-counting
+// framing of RFC 6587 unless NewlineFraming is set; on a local stream socket they are
+// terminated with LF, as local daemons expect.
+//
+// A failed write reconnects once and sends the message again.
+type SyslogWriter struct {
+	config   SyslogConfig
+	local    bool
+	hostname string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	network string // Network of conn; the local socket may be "unixgram" or "unix".
+	closed  bool
+}
+
+// NewSyslogWriter creates a SyslogWriter and connects it to the configured endpoint.
+func NewSyslogWriter(config SyslogConfig) (*SyslogWriter, error) {
+	switch config.Network {
+	case "", "unix", "unixgram", "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network: %s", config.Network)
+	}
+	if config.Network != "" && !strings.HasPrefix(config.Network, "unix") && config.Address == "" {
+		return nil, fmt.Errorf("syslog network %s requires an address", config.Network)
+	}
+	local := config.Network == "" || strings.HasPrefix(config.Network, "unix")
+	if config.Format == "" {
+		config.Format = SyslogRFC5424
+		if local {
+			config.Format = SyslogRFC3164
+		}
+	}
+	if config.Format != SyslogRFC5424 && config.Format != SyslogRFC3164 {
+		return nil, fmt.Errorf("unsupported syslog format: %s", config.Format)
+	}
+	if config.Facility <= 0 || config.Facility > FacilityLocal7 {
+		config.Facility = FacilityUser
+	}
+	if config.AppName == "" {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+	if config.StructuredDataID == "" {
+		config.StructuredDataID = defaultStructuredDataID
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	hostname, _ := os.Hostname()
+
+	w := &SyslogWriter{config: config, local: local, hostname: hostname}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write formats the entry as a syslog message and sends it.
+func (w *SyslogWriter) Write(entry any) error {
+	var e LogzEntry
+	switch v := entry.(type) {
+	case LogzEntry:
+		e = v
+	case []byte:
+		e = NewLogEntry().WithLevel(INFO).WithMessage(string(v))
+	default:
+		return fmt.Errorf("unsupported log entry type: %T", entry)
+	}
+	msg, err := w.format(e)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return err
+		}
+	}
+	if err := w.send(msg); err == nil {
+		return nil
+	}
+	// The daemon may have restarted: reconnect and send the message once more.
+	w.conn.Close()
+	w.conn = nil
+	if err := w.connect(); err != nil {
+		return err
+	}
+	return w.send(msg)
+}
+
+// Flush does nothing: every message is sent by Write.
+func (w *SyslogWriter) Flush(_ context.Context) error {
+	return nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	return w.conn.Close()
+}
+
+// connect opens the connection to the configured endpoint. The caller must hold w.mu,
+// unless w is not shared yet.
+func (w *SyslogWriter) connect() error {
+	dialer := &net.Dialer{Timeout: w.config.Timeout}
+	var conn net.Conn
+	var err error
+	network := w.config.Network
+
+	switch network {
+	case "":
+		conn, network, err = dialLocalSyslog(dialer, w.config.Address)
+	case "tls":
+		conn, err = tls.DialWithDialer(dialer, "tcp", w.config.Address, w.config.TLSConfig)
+	default:
+		address := w.config.Address
+		if address == "" {
+			address = syslogLocalSockets[0]
+		}
+		conn, err = dialer.Dial(w.config.Network, address)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to syslog: %w", err)
+	}
+	w.conn = conn
+	w.network = network
+	return nil
+}
+
+// dialLocalSyslog connects to the local syslog socket, trying datagram then stream sockets.
+// It returns the connection and its network.
+func dialLocalSyslog(dialer *net.Dialer, address string) (net.Conn, string, error) {
+	paths := syslogLocalSockets
+	if address != "" {
+		paths = []string{address}
+	}
+	var err error
+	for _, path := range paths {
+		for _, network := range []string{"unixgram", "unix"} {
+			var conn net.Conn
+			if conn, err = dialer.Dial(network, path); err == nil {
+				return conn, network, nil
+			}
+		}
+	}
+	return nil, "", err
+}
+
+// send writes one message with the framing of the connection. The caller must hold w.mu.
+func (w *SyslogWriter) send(msg string) error {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.config.Timeout)); err != nil {
+		return err
+	}
+	switch w.network {
+	case "unix":
+		msg += "\n"
+	case "tcp", "tls":
+		if w.config.NewlineFraming {
+			msg += "\n"
+		} else {
+			msg = strconv.Itoa(len(msg)) + " " + msg
+		}
+	}
+	_, err := w.conn.Write([]byte(msg))
+	return err
+}
+
+// format renders the entry as a syslog message, without framing.
+func (w *SyslogWriter) format(entry LogzEntry) (string, error) {
+	le := entryFields(entry)
+	severity, ok := syslogSeverities[le.Level]
+	if !ok {
+		severity = syslogSeverities[INFO]
+	}
+	pri := int(w.config.Facility)*8 + severity
+
+	hostname := le.Hostname
+	if hostname == "" {
+		hostname = w.hostname
+	}
+	pid := le.ProcessID
+	if pid == 0 {
+		pid = os.Getpid()
+	}
+	ts := le.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	var msg string
+	if w.config.Formatter != nil {
+		formatted, err := w.config.Formatter.Format(entry)
+		if err != nil {
+			return "", err
+		}
+		msg = formatted
+	}
+
+	var b strings.Builder
+	if w.config.Format == SyslogRFC3164 {
+		if w.config.Formatter == nil {
+			msg = syslogTextMessage(le)
+		}
+		fmt.Fprintf(&b, "<%d>%s ", pri, ts.Format(time.Stamp))
+		// Local daemons add the hostname themselves.
+		if !w.local {
+			b.WriteString(syslogHeaderField(hostname, 255))
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s[%d]: %s", syslogHeaderField(w.config.AppName, 32), pid, msg)
+		return b.String(), nil
+	}
+
+	if w.config.Formatter == nil {
+		msg = le.Message
+	}
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d %s ",
+		pri,
+		ts.Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(hostname, 255),
+		syslogHeaderField(w.config.AppName, 48),
+		pid,
+		syslogHeaderField(le.Source, 32),
+	)
+	b.WriteString(w.structuredData(le))
+	if msg != "" {
+		b.WriteByte(' ')
+		b.WriteString(msg)
+	}
+	return b.String(), nil
+}
+
+// structuredData renders the metadata, the tags, the trace ID and the error of the entry
+// as one RFC 5424 structured-data element, or "-" when there is none.
+func (w *SyslogWriter) structuredData(le LogEntry) string {
+	var b strings.Builder
+	if le.TraceID != "" {
+		writeSDParam(&b, "trace_id", le.TraceID)
+	}
+	if le.Error != nil {
+		writeSDParam(&b, "error", le.Error.Message)
+	}
+	for _, k := range sortedKeys(le.Tags) {
+		writeSDParam(&b, "tag."+k, le.Tags[k])
+	}
+	for _, k := range sortedKeys(le.Metadata) {
+		writeSDValue(&b, k, normalizeValue(le.Metadata[k]))
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return "[" + w.config.StructuredDataID + b.String() + "]"
+}
+
+// writeSDValue writes a metadata value as SD-PARAMs, flattening nested maps into dotted names.
+func writeSDValue(b *strings.Builder, name string, v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(val) {
+			writeSDValue(b, name+"."+k, normalizeValue(val[k]))
+		}
+	case []interface{}:
+		data, err := json.Marshal(val)
+		if err != nil {
+			writeSDParam(b, name, fmt.Sprintf("%v", val))
+			return
+		}
+		writeSDParam(b, name, string(data))
+	case nil:
+		writeSDParam(b, name, "")
+	default:
+		writeSDParam(b, name, fmt.Sprintf("%v", val))
+	}
+}
+
+// writeSDParam writes a PARAM-NAME="PARAM-VALUE" pair preceded by a space.
+// The name is restricted to 32 printable characters other than '=', ']', '"' and space,
+// and '"', '\' and ']' are escaped in the value.
+func writeSDParam(b *strings.Builder, name, value string) {
+	b.WriteByte(' ')
+	b.WriteString(syslogName(name, 32))
+	b.WriteString(`="`)
+	for _, r := range value {
+		if r == '"' || r == '\\' || r == ']' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+}
+
+// syslogHeaderField returns value as a header field: printable ASCII truncated to max
+// characters, or "-" when empty.
+func syslogHeaderField(value string, max int) string {
+	if value == "" {
+		return "-"
+	}
+	return syslogName(value, max)
+}
+
+// syslogName replaces the characters not allowed in a header field or a PARAM-NAME
+// by '_' and truncates the result to max characters.
+func syslogName(value string, max int) string {
+	if value == "" {
+		return "_"
+	}
+	name := []byte(value)
+	for i, c := range name {
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	if len(name) > max {
+		name = name[:max]
+	}
+	return string(name)
+}
+
+// syslogTextMessage returns the message of an RFC 3164 entry: the message followed by the
+// tags and the metadata as logfmt pairs, since the format has no structured data.
+func syslogTextMessage(le LogEntry) string {
+	var b strings.Builder
+	if le.TraceID != "" {
+		writeLogfmtPair(&b, "trace_id", le.TraceID)
+	}
+	if le.Error != nil {
+		writeLogfmtPair(&b, "error", le.Error.Message)
+	}
+	for _, k := range sortedKeys(le.Tags) {
+		writeLogfmtPair(&b, "tag."+logfmtKey(k), le.Tags[k])
+	}
+	for _, k := range sortedKeys(le.Metadata) {
+		writeLogfmtValue(&b, logfmtKey(k), normalizeValue(le.Metadata[k]))
+	}
+	if b.Len() == 0 {
+		return le.Message
+	}
+	return le.Message + " " + b.String()
+}
@@ -4,12 +4,16 @@ import (
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"runtime"
+	"strings"
+	"sync"
 )
 
 // LogFormatter defines the contract for formatting log entries.
@@ -20,11 +24,23 @@ type LogFormatter interface {
 }
 
 // JSONFormatter formats the log in JSON format.
-type JSONFormatter struct{}
+// Without a FieldMap the entry is marshalled as is; with one, the keys and
+// layouts of the mapping are used (see ECSFieldMap, GCPFieldMap and DatadogFieldMap).
+type JSONFormatter struct {
+	FieldMap *JSONFieldMap
+}
+
+// NewJSONFormatter creates a JSONFormatter writing entries with the given field mapping.
+func NewJSONFormatter(fieldMap JSONFieldMap) *JSONFormatter {
+	return &JSONFormatter{FieldMap: &fieldMap}
+}
 
 // Format converts the log entry to JSON.
 // Returns the JSON string and an error if marshalling fails.
 func (f *JSONFormatter) Format(entry LogzEntry) (string, error) {
+	if f.FieldMap != nil {
+		return f.FieldMap.format(entry)
+	}
 	data, err := json.Marshal(entry)
 	if err != nil {
 		return "", err
@@ -61,6 +77,8 @@ func (f *TextFormatter) Format(entry LogzEntry) (string, error) {
 			icon = "\033[33m⚠️\033[0m "
 		case ERROR:
 			icon = "\033[31m❌\033[0m "
+		case PANIC:
+			icon = "\033[35m🔥\033[0m "
 		case FATAL:
 			icon = "\033[35m💀\033[0m "
 		default:
@@ -87,6 +105,8 @@ func (f *TextFormatter) Format(entry LogzEntry) (string, error) {
 			levelStr = "\033[33mWARN\033[0m"
 		case ERROR:
 			levelStr = "\033[31mERROR\033[0m"
+		case PANIC:
+			levelStr = "\033[35mPANIC\033[0m"
 		case FATAL:
 			levelStr = "\033[35mFATAL\033[0m"
 		default:
@@ -155,8 +175,14 @@ func (f *TextFormatter) Format(entry LogzEntry) (string, error) {
 	// Construct the header
 	header := fmt.Sprintf("%s [%s] %s %s - ", timestamp, levelStr, context, icon)
 
+	// Error chain and stack, one line each
+	errText := ""
+	if info := entry.GetError(); info != nil {
+		errText = "\n  error: " + strings.ReplaceAll(info.String(), "\n", "\n  ")
+	}
+
 	// Return the formatted log entry
-	return fmt.Sprintf("%s%s%s", header, entry.GetMessage(), metadata), nil
+	return fmt.Sprintf("%s%s%s%s", header, entry.GetMessage(), errText, metadata), nil
 }
 
 // LogWriter defines the contract for writing logs.
@@ -164,11 +190,27 @@ type LogWriter[T any] interface {
 	Write(entry T) error
 }
 
+// LogBatchWriter is implemented by writers that can write several entries at once.
+type LogBatchWriter[T any] interface {
+	// WriteBatch writes the entries in order.
+	WriteBatch(entries []T) error
+}
+
+// LogFlusher is implemented by writers that buffer entries before writing them.
+type LogFlusher interface {
+	// Flush writes the buffered entries, giving up when ctx is done.
+	Flush(ctx context.Context) error
+}
+
+// ErrWriterClosed is returned when writing to a writer that has been closed.
+var ErrWriterClosed = errors.New("log writer closed")
+
 // NewDefaultWriter creates a new instance of DefaultWriter.
 // Takes an io.Writer and a LogFormatter as parameters.
 type DefaultWriter[T any] struct {
 	out       io.Writer
 	formatter LogFormatter
+	mu        sync.RWMutex
 }
 
 // NewDefaultWriter cria um novo VWriter usando generics.
@@ -184,14 +226,18 @@ func (w *DefaultWriter[T]) Write(entry T) error {
 	var formatted string
 	var err error
 
+	w.mu.RLock()
+	formatter := w.formatter
+	w.mu.RUnlock()
+
 	// Verifique se a entrada é do tipo LogzEntry
 	switch v := any(entry).(type) {
 	case LogzEntry:
-		formatted, err = w.formatter.Format(v)
+		formatted, err = formatter.Format(v)
 	case []byte:
 		// Converta o []byte em LogzEntry antes de formatar (exemplo simplificado)
 		entry := NewLogEntry().WithMessage(string(v))
-		formatted, err = w.formatter.Format(entry)
+		formatted, err = formatter.Format(entry)
 	default:
 		return fmt.Errorf("unsupported log entry type: %T", entry)
 	}
@@ -204,6 +250,13 @@ func (w *DefaultWriter[T]) Write(entry T) error {
 	return err
 }
 
+// SetFormatter replaces the formatter used by the writer.
+func (w *DefaultWriter[T]) SetFormatter(formatter LogFormatter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.formatter = formatter
+}
+
 // formatMetadata converts VMetadata to a JSON string.
 // Returns the JSON string or an empty string if marshalling fails.
 func formatMetadata(entry LogzEntry) string {
@@ -212,36 +265,11 @@ func formatMetadata(entry LogzEntry) string {
 		return ""
 	}
 	prefix := "Context:\n"
-	for k, v := range metadata {
+	for _, k := range sortedKeys(metadata) {
 		if k == "showContext" {
 			continue
 		}
-		prefix += fmt.Sprintf("  - %s: %v\n", k, v)
+		prefix += fmt.Sprintf("  - %s: %v\n", k, metadata[k])
 	}
 	return prefix
 }
-
-type LogMultiWriter[T any] interface {
-	Write(entry T) error
-	AddWriter(w LogWriter[T])
-	GetWriters() []LogWriter[T]
-}
-
-type MultiWriter[T any] struct {
-	writers []LogWriter[T]
-}
-
-func (mw *MultiWriter[T]) AddWriter(w LogWriter[T]) {
-	mw.writers = append(mw.writers, w)
-}
-
-func (mw *MultiWriter[T]) Write(entry T) error {
-	for _, w := range mw.writers {
-		if err := w.Write(entry); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (mw *MultiWriter[T]) GetWriters() []LogWriter[T] { return mw.writers }
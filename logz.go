@@ -1,13 +1,18 @@
 package logz
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/faelmori/logz/internal/core"
 	logz "github.com/faelmori/logz/logger"
 	vs "github.com/faelmori/logz/version"
+	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"sync"
+	"time"
 )
 
 var (
@@ -30,6 +35,95 @@ type Logger interface{ logz.LogzLogger }
 
 type JSONFormatter = core.JSONFormatter
 type TextFormatter = core.TextFormatter
+type YAMLFormatter = core.YAMLFormatter
+type XMLFormatter = core.XMLFormatter
+type RawFormatter = core.RawFormatter
+type LogfmtFormatter = core.LogfmtFormatter
+type JSONFieldMap = core.JSONFieldMap
+type LogParser = core.LogParser
+type JSONParser = core.JSONParser
+type LogfmtParser = core.LogfmtParser
+type SlogHandler = core.SlogHandler
+type LogzEntry = core.LogzEntry
+type LogWriter = core.LogWriter[any]
+type AsyncWriter = core.AsyncWriter
+type AsyncWriterConfig = core.AsyncWriterConfig
+type OverflowPolicy = core.OverflowPolicy
+type RotatingFileWriter = core.RotatingFileWriter
+type RotatingFileConfig = core.RotatingFileConfig
+type RotationInterval = core.RotationInterval
+type MultiWriter = core.MultiWriter[any]
+type WriterOptions = core.WriterOptions
+type Hook = core.Hook
+type MultiHook = core.MultiHook
+type Secret = core.Secret
+type Redactor = core.Redactor
+type RedactPattern = core.RedactPattern
+type RedactingWriter = core.RedactingWriter
+type DedupWriter = core.DedupWriter
+type DedupConfig = core.DedupConfig
+type Sampler = core.Sampler
+type SamplingConfig = core.SamplingConfig
+type SamplingRule = core.SamplingRule
+type SamplingKey = core.SamplingKey
+type LevelOverride = core.LevelOverride
+type AdminConfig = core.AdminConfig
+type AdminClient = core.AdminClient
+type LevelsInfo = core.LevelsInfo
+type LoggerInfo = core.LoggerInfo
+type FatalMode = core.FatalMode
+type PanicError = core.PanicError
+type ErrorInfo = core.ErrorInfo
+type RecoveryPolicy = core.RecoveryPolicy
+type RecoveryConfig = core.RecoveryConfig
+type Field = core.Field
+type FieldType = core.FieldType
+type ObjectMarshaler = core.ObjectMarshaler
+type SyslogWriter = core.SyslogWriter
+type SyslogConfig = core.SyslogConfig
+type SyslogFormat = core.SyslogFormat
+type SyslogFacility = core.SyslogFacility
+type JournaldWriter = core.JournaldWriter
+type JournaldConfig = core.JournaldConfig
+type LokiWriter = core.LokiWriter
+type LokiConfig = core.LokiConfig
+type RetryConfig = core.RetryConfig
+type PushError = core.PushError
+type ElasticWriter = core.ElasticWriter
+type ElasticConfig = core.ElasticConfig
+type HTTPBatchWriter = core.HTTPBatchWriter
+type HTTPBatchConfig = core.HTTPBatchConfig
+type BatchEncoder = core.BatchEncoder
+type NDJSONEncoder = core.NDJSONEncoder
+type JSONArrayEncoder = core.JSONArrayEncoder
+type SplunkHECEncoder = core.SplunkHECEncoder
+
+// RecoveryPolicy values, see SetRecovery.
+const (
+	RecoverContinue = core.RecoverContinue
+	RecoverRepanic  = core.RecoverRepanic
+)
+
+// FatalMode values, see SetFatalMode.
+const (
+	FatalExit  = core.FatalExit
+	FatalPanic = core.FatalPanic
+)
+
+// Overflow policies of an AsyncWriter.
+const (
+	OverflowBlock          = core.OverflowBlock
+	OverflowDropNewest     = core.OverflowDropNewest
+	OverflowDropOldest     = core.OverflowDropOldest
+	OverflowDropBelowLevel = core.OverflowDropBelowLevel
+)
+
+// Custom slog levels for the logz levels that have no slog counterpart.
+const (
+	SlogLevelTrace   = core.SlogLevelTrace
+	SlogLevelNotice  = core.SlogLevelNotice
+	SlogLevelSuccess = core.SlogLevelSuccess
+)
 
 type Writer struct{ core.LogWriter[any] }
 
@@ -86,6 +180,152 @@ func NewWriter(out *os.File, formatter core.LogFormatter) Writer {
 	return Writer{LogWriter: core.NewDefaultWriter[any](out, formatter)}
 }
 
+// Output formats accepted by SetLogFormat and NewFormatter.
+const (
+	JSON = core.JSON
+	TEXT = core.TEXT
+	YAML = core.YAML
+	XML  = core.XML
+	RAW  = core.RAW
+
+	LOGFMT = core.LOGFMT
+)
+
+// NewFormatter returns the formatter of the given format, or nil if the format is unknown.
+func NewFormatter(format LogFormat) core.LogFormatter {
+	return core.NewFormatter(format)
+}
+
+// Timestamp layouts of a JSONFieldMap that write the time as a number.
+const (
+	TimeFormatUnix      = core.TimeFormatUnix
+	TimeFormatUnixMilli = core.TimeFormatUnixMilli
+	TimeFormatUnixNano  = core.TimeFormatUnixNano
+)
+
+// NewJSONFormatter creates a JSONFormatter writing entries with the given field mapping.
+func NewJSONFormatter(fieldMap JSONFieldMap) *JSONFormatter {
+	return core.NewJSONFormatter(fieldMap)
+}
+
+// ECSFieldMap returns the JSON field mapping for the Elastic Common Schema.
+func ECSFieldMap() JSONFieldMap { return core.ECSFieldMap() }
+
+// GCPFieldMap returns the JSON field mapping for Google Cloud Logging.
+func GCPFieldMap() JSONFieldMap { return core.GCPFieldMap() }
+
+// DatadogFieldMap returns the JSON field mapping for Datadog.
+func DatadogFieldMap() JSONFieldMap { return core.DatadogFieldMap() }
+
+// NewParser returns the parser of the given format, or nil if the format cannot be read back.
+func NewParser(format LogFormat) LogParser {
+	return core.NewParser(format)
+}
+
+// NewMultiWriter creates a writer fanning entries out to the given writers concurrently.
+// Use AddWriterWithOptions to give a writer its own level, filter or timeout.
+func NewMultiWriter(writers ...LogWriter) *MultiWriter {
+	return core.NewMultiWriter[any](writers...)
+}
+
+// Groupings of a Sampler.
+const (
+	SampleByMessage = core.SampleByMessage
+	SampleByCaller  = core.SampleByCaller
+)
+
+// NewSampler creates a sampler keeping the first entries of each group per tick, then every Mth one.
+// Attach it to a logger with SetSampler.
+func NewSampler(config SamplingConfig) *Sampler { return core.NewSampler(config) }
+
+// DefaultRedactor returns a Redactor masking the usual credential keys, e-mail addresses,
+// JWTs, bearer tokens and card numbers.
+func DefaultRedactor() *Redactor { return core.DefaultRedactor() }
+
+// Built-in redaction patterns.
+var (
+	RedactEmails       = core.RedactEmails
+	RedactJWTs         = core.RedactJWTs
+	RedactBearerTokens = core.RedactBearerTokens
+	RedactCardNumbers  = core.RedactCardNumbers
+)
+
+// NewRedactingWriter creates a writer redacting entries before writing them to out.
+func NewRedactingWriter(out LogWriter, redactor *Redactor) *RedactingWriter {
+	return core.NewRedactingWriter(out, redactor)
+}
+
+// NewDedupWriter creates a writer collapsing consecutive identical entries into a summary entry.
+func NewDedupWriter(out LogWriter, config DedupConfig) *DedupWriter {
+	return core.NewDedupWriter(out, config)
+}
+
+// NewAsyncWriter creates a writer that queues entries and writes them to out in the background.
+func NewAsyncWriter(out LogWriter, config AsyncWriterConfig) *AsyncWriter {
+	return core.NewAsyncWriter(out, config)
+}
+
+// Time schedules of a RotatingFileWriter.
+const (
+	RotateNever  = core.RotateNever
+	RotateHourly = core.RotateHourly
+	RotateDaily  = core.RotateDaily
+)
+
+// NewRotatingFileWriter creates a file writer that rotates in-process on size and time.
+func NewRotatingFileWriter(config RotatingFileConfig) (*RotatingFileWriter, error) {
+	return core.NewRotatingFileWriter(config)
+}
+
+// Header layouts of a SyslogWriter.
+const (
+	SyslogRFC5424 = core.SyslogRFC5424
+	SyslogRFC3164 = core.SyslogRFC3164
+)
+
+// Common facilities of a SyslogWriter.
+const (
+	FacilityUser   = core.FacilityUser
+	FacilityDaemon = core.FacilityDaemon
+	FacilityAuth   = core.FacilityAuth
+	FacilityLocal0 = core.FacilityLocal0
+	FacilityLocal1 = core.FacilityLocal1
+	FacilityLocal2 = core.FacilityLocal2
+	FacilityLocal3 = core.FacilityLocal3
+	FacilityLocal4 = core.FacilityLocal4
+	FacilityLocal5 = core.FacilityLocal5
+	FacilityLocal6 = core.FacilityLocal6
+	FacilityLocal7 = core.FacilityLocal7
+)
+
+// NewSyslogWriter creates a writer sending entries to a local or remote syslog daemon.
+func NewSyslogWriter(config SyslogConfig) (*SyslogWriter, error) {
+	return core.NewSyslogWriter(config)
+}
+
+// NewJournaldWriter creates a writer sending structured entries to systemd-journald.
+func NewJournaldWriter(config JournaldConfig) (*JournaldWriter, error) {
+	return core.NewJournaldWriter(config)
+}
+
+// JournalStreamConnected reports whether the standard error of the process is connected to journald.
+func JournalStreamConnected() bool { return core.JournalStreamConnected() }
+
+// NewLokiWriter creates a writer batching entries and pushing them to Grafana Loki.
+func NewLokiWriter(config LokiConfig) (*LokiWriter, error) {
+	return core.NewLokiWriter(config)
+}
+
+// NewElasticWriter creates a writer indexing entries in Elasticsearch or OpenSearch through the _bulk API.
+func NewElasticWriter(config ElasticConfig) (*ElasticWriter, error) {
+	return core.NewElasticWriter(config)
+}
+
+// NewHTTPBatchWriter creates a writer sending batches of entries to an HTTP endpoint, e.g. Splunk HEC.
+func NewHTTPBatchWriter(config HTTPBatchConfig) (*HTTPBatchWriter, error) {
+	return core.NewHTTPBatchWriter(config)
+}
+
 // initializeLogger initializes the global logger with the given prefix.
 func initializeLogger(prefix string) {
 	//	once.Do(func() {
@@ -96,11 +336,12 @@ func initializeLogger(prefix string) {
 		return
 	}
 	logger = logz.NewLogger(prefix).(Logger)
-	logLevel := os.Getenv("LOG_LEVEL")
-	if logLevel != "" {
-		logger.SetLevel(core.LogLevel(logLevel))
-	} else {
-		logger.SetLevel(core.INFO)
+	logger.SetLevel(core.INFO)
+	// LOG_LEVEL accepts a default level and per-module levels, e.g. "info,db=debug"
+	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
+		if err := logger.SetLevelSpec(logLevel); err != nil {
+			log.Printf("Invalid LOG_LEVEL value: %v", err)
+		}
 	}
 	logFormat := os.Getenv("LOG_FORMAT")
 	//config := logger.GetConfig().(*core.Config)
@@ -132,6 +373,16 @@ func NewLogger(prefix string) Logger {
 	return logz.NewLogger(prefix)
 }
 
+// NewSlogHandler creates a slog.Handler writing through the given logger.
+func NewSlogHandler(lgr Logger) *SlogHandler {
+	return core.NewSlogHandler(lgr)
+}
+
+// NewSlogLogger creates a *slog.Logger writing through the given logger.
+func NewSlogLogger(lgr Logger) *slog.Logger {
+	return core.NewSlogLogger(lgr)
+}
+
 // SetLogger sets the global core instance to the provided core.
 func SetLogger(newLogger Logger) {
 	//mu.Lock()
@@ -162,6 +413,44 @@ func SetLogLevel(level LogLevel) {
 	}
 }
 
+// SetLogLevelSpec applies a level specification such as "info,db=debug,http.client=warn" to the global core.
+func SetLogLevelSpec(spec string) error {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	return logger.SetLevelSpec(spec)
+}
+
+// SetModuleLevel sets the level of the loggers of the global core named after module.
+func SetModuleLevel(module string, level LogLevel) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.SetModuleLevel(module, level)
+}
+
+// SetLevelOverride sets a temporary level of the global core for module ("" for the default level)
+// that expires after the TTL.
+func SetLevelOverride(module string, level LogLevel, ttl time.Duration) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.SetLevelOverride(module, level, ttl)
+}
+
+// RegisterAdminHandlers registers the admin API of the global core on mux (see AdminConfig).
+func RegisterAdminHandlers(mux *http.ServeMux, config AdminConfig) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	core.RegisterAdminHandlers(mux, logger, config)
+}
+
+// ParseLevelSpec parses a level specification such as "info,db=debug".
+func ParseLevelSpec(spec string) (LogLevel, map[string]LogLevel, error) {
+	return core.ParseLevelSpec(spec)
+}
+
 // GetLogLevel returns the log level of the global core.
 func GetLogLevel() LogLevel {
 	if logger == nil {
@@ -198,6 +487,86 @@ func SetMetadata(key string, value interface{}) {
 	}
 }
 
+// With returns a child of the global core carrying the given fields.
+func With(fields map[string]interface{}) Logger {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	return logger.With(fields)
+}
+
+// Named returns a child of the global core with the given name appended to its prefix.
+func Named(name string) Logger {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	return logger.Named(name)
+}
+
+// WithError returns a child of the global core recording err, with its chain, in every entry.
+func WithError(err error) Logger {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	return logger.WithError(err)
+}
+
+// SetStackTraceLevel sets the lowest level at which entries of the global core carrying
+// an error capture the call stack; an empty level disables it.
+func SetStackTraceLevel(level LogLevel) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.SetStackTraceLevel(level)
+}
+
+// NewErrorInfo records the message, the concrete type and the wrapped errors of err.
+func NewErrorInfo(err error) *ErrorInfo { return core.NewErrorInfo(err) }
+
+// String returns a typed field holding a string, for the level methods of a Logger.
+func String(key, value string) Field { return core.String(key, value) }
+
+// Int returns a typed field holding an int.
+func Int(key string, value int) Field { return core.Int(key, value) }
+
+// Int64 returns a typed field holding an int64.
+func Int64(key string, value int64) Field { return core.Int64(key, value) }
+
+// Float64 returns a typed field holding a float64.
+func Float64(key string, value float64) Field { return core.Float64(key, value) }
+
+// Bool returns a typed field holding a bool.
+func Bool(key string, value bool) Field { return core.Bool(key, value) }
+
+// Duration returns a typed field holding a time.Duration.
+func Duration(key string, value time.Duration) Field { return core.Duration(key, value) }
+
+// Err returns a typed field recording err, with its chain, as the error of the entry.
+func Err(err error) Field { return core.Err(err) }
+
+// Any returns a typed field holding an arbitrary value.
+func Any(key string, value interface{}) Field { return core.Any(key, value) }
+
+// Object returns a typed field holding the fields of value as a nested map.
+func Object(key string, value ObjectMarshaler) Field { return core.Object(key, value) }
+
+// AddHook appends a hook to the chain of the global core.
+// The hook sees every entry before it is written and can rewrite or drop it.
+func AddHook(hook Hook) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.AddHook(hook)
+}
+
+// AddMultiHook appends a hook that can also duplicate entries to the chain of the global core.
+func AddMultiHook(hook MultiHook) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.AddMultiHook(hook)
+}
+
 // Trace logs a trace message with the given context.
 func TraceCtx(msg string, ctx map[string]interface{}) {
 	//mu.RLock()
@@ -270,6 +639,167 @@ func FatalCtx(msg string, ctx map[string]interface{}) {
 	}
 }
 
+// PanicCtx logs a panic message with the given context and panics with a *PanicError.
+func PanicCtx(msg string, ctx map[string]interface{}) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.PanicCtx(msg, ctx)
+}
+
+// TraceContext logs a trace message with the request-scoped values of ctx.
+func TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.TraceContext(ctx, msg, fields...)
+	}
+}
+
+// NoticeContext logs a notice message with the request-scoped values of ctx.
+func NoticeContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.NoticeContext(ctx, msg, fields...)
+	}
+}
+
+// SuccessContext logs a success message with the request-scoped values of ctx.
+func SuccessContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.SuccessContext(ctx, msg, fields...)
+	}
+}
+
+// DebugContext logs a debug message with the request-scoped values of ctx.
+func DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.DebugContext(ctx, msg, fields...)
+	}
+}
+
+// InfoContext logs an info message with the request-scoped values of ctx.
+func InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.InfoContext(ctx, msg, fields...)
+	}
+}
+
+// WarnContext logs a warning message with the request-scoped values of ctx.
+func WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.WarnContext(ctx, msg, fields...)
+	}
+}
+
+// ErrorContext logs an error message with the request-scoped values of ctx.
+func ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.ErrorContext(ctx, msg, fields...)
+	}
+}
+
+// FatalContext logs a fatal message with the request-scoped values of ctx and exits the application.
+func FatalContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger != nil {
+		logger.FatalContext(ctx, msg, fields...)
+	}
+}
+
+// PanicContext logs a panic message with the request-scoped values of ctx and panics.
+func PanicContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.PanicContext(ctx, msg, fields...)
+}
+
+// SetExitFunc sets the function called by FATAL entries of the global core to terminate
+// the process; nil restores os.Exit.
+func SetExitFunc(exit func(code int)) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.SetExitFunc(exit)
+}
+
+// SetRecovery sets how the panics recovered by RecoverAndLog and Go are handled.
+func SetRecovery(config RecoveryConfig) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.SetRecovery(config)
+}
+
+// RecoverAndLog recovers a panic and logs it with the global core, with the panic value
+// and the stack of the goroutine, then re-panics or continues according to SetRecovery.
+// It must be deferred directly: defer logz.RecoverAndLog().
+func RecoverAndLog() {
+	if value := recover(); value != nil {
+		if logger == nil {
+			logger = logz.NewLogger(pfx)
+		}
+		logger.HandlePanic(value)
+	}
+}
+
+// Go runs f in a new goroutine whose panics are handled by RecoverAndLog.
+func Go(f func()) {
+	go func() {
+		defer RecoverAndLog()
+		f()
+	}()
+}
+
+// SetFatalMode sets whether FATAL entries of the global core exit the process or panic.
+func SetFatalMode(mode FatalMode) {
+	if logger == nil {
+		logger = logz.NewLogger(pfx)
+	}
+	logger.SetFatalMode(mode)
+}
+
+// Flush flushes the writer and the notifiers of the global core.
+func Flush(ctx context.Context) error {
+	if logger == nil {
+		return nil
+	}
+	return logger.Flush(ctx)
+}
+
+// Close flushes and closes the writer and the notifiers of the global core.
+func Close() error {
+	if logger == nil {
+		return nil
+	}
+	return logger.Close()
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return core.ContextWithRequestID(ctx, requestID)
+}
+
+// ContextWithTraceID returns a copy of ctx carrying the given trace ID.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return core.ContextWithTraceID(ctx, traceID)
+}
+
+// ContextWithSpanID returns a copy of ctx carrying the given span ID.
+func ContextWithSpanID(ctx context.Context, spanID string) context.Context {
+	return core.ContextWithSpanID(ctx, spanID)
+}
+
+// ContextWithLogger returns a copy of ctx carrying a scoped logger.
+func ContextWithLogger(ctx context.Context, lgr Logger) context.Context {
+	return core.ContextWithLogger(ctx, lgr)
+}
+
+// LoggerFromContext returns the scoped logger stored in ctx, or nil.
+func LoggerFromContext(ctx context.Context) Logger {
+	if lgr, ok := core.LoggerFromContext(ctx).(Logger); ok {
+		return lgr
+	}
+	return nil
+}
+
 // AddNotifier adds a notifier to the global core's configuration.
 func AddNotifier(name string, notifier Notifier) {
 	//mu.Lock()
@@ -310,9 +840,7 @@ func SetLogFormat(format LogFormat) {
 	//mu.Lock()
 	//defer mu.Unlock()
 	if logger != nil {
-		/*logger.
-		GetConfig().
-		SetFormat(format)*/
+		logger.SetFormat(format)
 	}
 }
 
@@ -453,10 +981,10 @@ func Success(args ...any) {
 	logger.SuccessCtx(fmt.Sprint(args...), nil)
 }
 
-// Panic returns the log output of the global core.
+// Panic logs a panic message with the global core and panics with a *PanicError.
 func Panic(args ...any) {
 	if logger == nil {
 		logger = logz.NewLogger(pfx)
 	}
-	logger.FatalCtx(fmt.Sprint(args...), nil)
+	logger.PanicCtx(fmt.Sprint(args...), nil)
 }
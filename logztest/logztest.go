@@ -0,0 +1,213 @@
+// Package logztest provides helpers for testing code that logs with logz.
+//
+// A Recorder is a LogWriter keeping the entries it receives, so tests assert on
+// levels, messages and fields instead of parsing formatted output:
+//
+//	logger, rec := logztest.NewLogger(t)
+//	svc := NewService(logger)
+//	svc.Start()
+//	rec.AssertContains(t, logz.INFO, "service started")
+//	rec.AssertCount(t, logz.ERROR, 0)
+//
+// The captured entries are printed through t.Log when the test fails.
+package logztest
+
+import (
+	il "github.com/faelmori/logz/internal/core"
+
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// FixedTime is the timestamp written by a Formatter whose Time is not set.
+var FixedTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Recorder is a LogWriter keeping every entry it receives in memory.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []il.LogzEntry
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Write records a copy of the entry. Values other than LogzEntry are rejected.
+func (r *Recorder) Write(entry any) error {
+	e, ok := entry.(il.LogzEntry)
+	if !ok {
+		return fmt.Errorf("logztest: unexpected entry type %T", entry)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e.Clone())
+	return nil
+}
+
+// Entries returns the recorded entries, oldest first.
+func (r *Recorder) Entries() []il.LogzEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]il.LogzEntry(nil), r.entries...)
+}
+
+// Messages returns the messages of the recorded entries, oldest first.
+func (r *Recorder) Messages() []string {
+	entries := r.Entries()
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.GetMessage()
+	}
+	return msgs
+}
+
+// Len returns the number of recorded entries.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// Filter returns the recorded entries of the given level.
+func (r *Recorder) Filter(level il.LogLevel) []il.LogzEntry {
+	var matched []il.LogzEntry
+	for _, e := range r.Entries() {
+		if e.GetLevel() == level {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Find returns the first entry of the given level whose message contains message, or nil.
+func (r *Recorder) Find(level il.LogLevel, message string) il.LogzEntry {
+	for _, e := range r.Entries() {
+		if e.GetLevel() == level && strings.Contains(e.GetMessage(), message) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Count returns the number of recorded entries of the given level.
+func (r *Recorder) Count(level il.LogLevel) int {
+	return len(r.Filter(level))
+}
+
+// AssertContains reports an error unless an entry of the given level has a message
+// containing message. It returns the first matching entry, or nil.
+func (r *Recorder) AssertContains(t testing.TB, level il.LogLevel, message string) il.LogzEntry {
+	t.Helper()
+	e := r.Find(level, message)
+	if e == nil {
+		t.Errorf("logztest: no %s entry containing %q in:\n%s", level, message, r.dump())
+	}
+	return e
+}
+
+// AssertNotContains reports an error if an entry of the given level has a message containing message.
+func (r *Recorder) AssertNotContains(t testing.TB, level il.LogLevel, message string) {
+	t.Helper()
+	if e := r.Find(level, message); e != nil {
+		t.Errorf("logztest: unexpected %s entry %q", level, e.GetMessage())
+	}
+}
+
+// AssertCount reports an error unless exactly n entries of the given level were recorded.
+func (r *Recorder) AssertCount(t testing.TB, level il.LogLevel, n int) {
+	t.Helper()
+	if got := r.Count(level); got != n {
+		t.Errorf("logztest: expected %d %s entries, got %d in:\n%s", n, level, got, r.dump())
+	}
+}
+
+// AssertField reports an error unless the metadata of entry holds want under key.
+// Values are compared with reflect.DeepEqual. A nil entry is ignored, so the result of
+// AssertContains can be passed as is.
+func AssertField(t testing.TB, entry il.LogzEntry, key string, want interface{}) {
+	t.Helper()
+	if entry == nil {
+		return
+	}
+	got, ok := entry.GetMetadata()[key]
+	if !ok {
+		t.Errorf("logztest: entry %q has no field %q", entry.GetMessage(), key)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logztest: field %q of entry %q: expected %#v, got %#v", key, entry.GetMessage(), want, got)
+	}
+}
+
+// dump formats the recorded entries, one per line.
+func (r *Recorder) dump() string {
+	var b strings.Builder
+	f := &Formatter{}
+	for _, e := range r.Entries() {
+		line, err := f.Format(e)
+		if err != nil {
+			line = e.String()
+		}
+		b.WriteString("\t")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	if b.Len() == 0 {
+		return "\t(no entries)\n"
+	}
+	return b.String()
+}
+
+// Formatter formats entries deterministically, for golden files and test output:
+// a logfmt line without colors or icons, whose timestamp is Time and which leaves
+// out the caller, hostname and pid.
+type Formatter struct {
+	Time time.Time // Timestamp of every entry; FixedTime when zero.
+}
+
+// Format converts the log entry to a deterministic logfmt line.
+func (f *Formatter) Format(entry il.LogzEntry) (string, error) {
+	ts := f.Time
+	if ts.IsZero() {
+		ts = FixedTime
+	}
+	e := entry.Clone().
+		WithTimestamp(ts).
+		WithCaller("").
+		WithHostname("").
+		WithProcessID(0)
+	return (&il.LogfmtFormatter{}).Format(e)
+}
+
+// NewLogger returns a logger recording every entry, DEBUG included, into the returned Recorder.
+// The entries are printed through t.Log when the test fails. A FATAL entry reports an error
+// instead of exiting the test binary.
+func NewLogger(t testing.TB) (il.LogzLogger, *Recorder) {
+	t.Helper()
+	rec := NewRecorder()
+	logger := il.NewLogger("test")
+	logger.SetWriter(rec)
+	logger.SetLevel(il.DEBUG)
+	logger.SetExitFunc(func(code int) {
+		t.Errorf("logztest: FATAL entry logged, exit status %d", code)
+	})
+	t.Cleanup(func() {
+		if t.Failed() && rec.Len() > 0 {
+			t.Logf("logztest: captured logs:\n%s", rec.dump())
+		}
+	})
+	return logger, rec
+}
@@ -0,0 +1,79 @@
+package logztest
+
+import (
+	il "github.com/faelmori/logz/internal/core"
+
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeTB records the failures reported by the assertions.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorderAssertions(t *testing.T) {
+	logger, rec := NewLogger(t)
+	logger.Named("db").DebugCtx("connecting", nil)
+	logger.With(map[string]interface{}{"user": "ana"}).InfoCtx("login succeeded", map[string]interface{}{"attempts": 2})
+	logger.WarnCtx("slow query", nil)
+
+	e := rec.AssertContains(t, il.INFO, "login")
+	AssertField(t, e, "user", "ana")
+	AssertField(t, e, "attempts", 2)
+	rec.AssertCount(t, il.DEBUG, 1)
+	rec.AssertNotContains(t, il.ERROR, "slow")
+	if got := rec.Messages(); len(got) != 3 || got[2] != "slow query" {
+		t.Errorf("unexpected messages: %v", got)
+	}
+
+	fake := &fakeTB{}
+	rec.AssertContains(fake, il.ERROR, "login")
+	rec.AssertCount(fake, il.WARN, 2)
+	AssertField(fake, e, "user", "bob")
+	AssertField(fake, e, "missing", nil)
+	if len(fake.errors) != 4 {
+		t.Errorf("expected 4 failures, got %d: %v", len(fake.errors), fake.errors)
+	}
+
+	rec.Reset()
+	exited := 0
+	logger.SetExitFunc(func(code int) { exited = code })
+	logger.FatalCtx("fatal in test", nil)
+	rec.AssertContains(t, il.FATAL, "fatal in test")
+	if exited != 1 || rec.Len() != 1 {
+		t.Errorf("expected one FATAL entry and exit status 1, got %d entries and %d", rec.Len(), exited)
+	}
+}
+
+func TestFormatterIsDeterministic(t *testing.T) {
+	f := &Formatter{}
+	entry := il.NewLogEntry().
+		WithLevel(il.WARN).
+		WithMessage("disk almost full").
+		WithSource("test.disk").
+		WithHostname("host-1").
+		WithProcessID(42).
+		AddMetadata("free", "2%")
+	entry.WithTimestamp(time.Now())
+
+	got, err := f.Format(entry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `ts=2000-01-01T00:00:00Z level=warn msg="disk almost full" source=test.disk free=2%`
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if entry.GetTimestamp().Equal(FixedTime) {
+		t.Error("the formatter must not modify the entry")
+	}
+}